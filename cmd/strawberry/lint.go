@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/GlitchedNexus/strawberry-tui/pkg/theme"
+)
+
+// cmdLint checks the class strings in Go sources: `Class: "..."` fields and
+// string literals passed to WithClass or ParseClass. Paths are files or
+// directories (walked recursively; "./..." works too) and default to ".".
+// With -theme <file>, token names are checked against that theme. A file
+// that doesn't parse is reported and the walk goes on.
+func cmdLint(args []string) error {
+	fl := flag.NewFlagSet("lint", flag.ContinueOnError)
+	themePath := fl.String("theme", "", "theme file (yaml, json or toml) to check tokens against")
+	if err := fl.Parse(args); err != nil { return err }
+	args = fl.Args()
+	if len(args) == 0 { args = []string{"."} }
+	tokens := theme.Default().Tokens
+	if *themePath != "" {
+		th, err := theme.LoadFile(*themePath)
+		if err != nil { return err }
+		tokens = th.Tokens
+	}
+	problems, err := lintPaths(os.Stdout, args, tokens)
+	if err != nil { return err }
+	if problems > 0 { return fmt.Errorf("%d class problem(s)", problems) }
+	return nil
+}
+
+// lintPaths lints the Go files under args, printing problems to out, and
+// returns how many it found. Only a path that can't be walked at all is an
+// error.
+func lintPaths(out io.Writer, args []string, tokens theme.Tokens) (int, error) {
+	fset := token.NewFileSet()
+	problems := 0
+	for _, arg := range args {
+		root := strings.TrimSuffix(arg, "...")
+		if root == "" { root = "." }
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path == root { return err }
+				fmt.Fprintln(out, err)
+				problems++
+				return nil
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")) { return filepath.SkipDir }
+				return nil
+			}
+			if !strings.HasSuffix(path, ".go") { return nil }
+			problems += lintFile(out, fset, path, tokens)
+			return nil
+		})
+		if err != nil { return problems, err }
+	}
+	return problems, nil
+}
+
+// lintFile prints "file:line:col: utility: problem" for each bad utility in
+// path, or the parse error when path isn't valid Go, and returns how many
+// problems it found.
+func lintFile(out io.Writer, fset *token.FileSet, path string, tokens theme.Tokens) int {
+	f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+	problems := 0
+	ast.Inspect(f, func(n ast.Node) bool {
+		var lit ast.Expr
+		switch n := n.(type) {
+		case *ast.KeyValueExpr:
+			if id, ok := n.Key.(*ast.Ident); ok && id.Name == "Class" { lit = n.Value }
+		case *ast.CallExpr:
+			if isClassCall(n.Fun) && len(n.Args) > 0 { lit = n.Args[0] }
+		}
+		bl, ok := lit.(*ast.BasicLit)
+		if !ok || bl.Kind != token.STRING { return true }
+		s, err := strconv.Unquote(bl.Value)
+		if err != nil { return true }
+		_, errs := theme.ParseClassStrict(s, tokens)
+		for _, e := range errs {
+			// Offsets map onto the source when the literal has no escapes.
+			pos := bl.Pos()
+			if len(s)+2 == len(bl.Value) { pos += token.Pos(1 + e.Pos) }
+			fmt.Fprintf(out, "%s: %s: %s\n", fset.Position(pos), e.Class, e.Msg)
+			problems++
+		}
+		return true
+	})
+	return problems
+}
+
+// isClassCall reports whether fun takes a class string as its first
+// argument: WithClass, ParseClass or ParseClassStrict, qualified or not.
+func isClassCall(fun ast.Expr) bool {
+	var name string
+	switch fun := fun.(type) {
+	case *ast.Ident: name = fun.Name
+	case *ast.SelectorExpr: name = fun.Sel.Name
+	}
+	return name == "WithClass" || name == "ParseClass" || name == "ParseClassStrict"
+}
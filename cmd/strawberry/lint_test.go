@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/pkg/theme"
+)
+
+func TestLintPaths(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a/broken.go": "package a\n\nfunc {\n",
+		"b/view.go": `package b
+
+import "github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+
+var ok = ui.Box("ok", ui.WithClass("p-2 fg-primary"))
+var bad = ui.Box("bad", ui.WithClass("p-2 fg-nope"))
+var alsoBad = WithClass("bold blnk")
+`,
+		"c/spec.go": "package c\n\nvar s = Spec{Class: \"px-x\"}\nvar _ = theme.ParseClass(\"md:bold\")\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { t.Fatal(err) }
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil { t.Fatal(err) }
+	}
+
+	var out strings.Builder
+	n, err := lintPaths(&out, []string{dir + "/..."}, theme.Default().Tokens)
+	if err != nil { t.Fatalf("lintPaths: %v", err) }
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []string{
+		filepath.Join(dir, "a/broken.go") + ":3:6: expected 'IDENT', found '{'",
+		filepath.Join(dir, "b/view.go") + ":6:43: fg-nope: ",
+		filepath.Join(dir, "b/view.go") + ":7:31: blnk: ",
+		filepath.Join(dir, "c/spec.go") + ":3:22: px-x: ",
+	}
+	if n != len(want) || len(lines) != len(want) { t.Fatalf("got %d problems:\n%s\nwant %d", n, out.String(), len(want)) }
+	for i, w := range want {
+		if !strings.HasPrefix(lines[i], w) { t.Errorf("line %d = %q, want prefix %q", i, lines[i], w) }
+	}
+
+	if _, err := lintPaths(&out, []string{filepath.Join(dir, "missing")}, theme.Default().Tokens); err == nil { t.Error("linting a missing path didn't fail") }
+}
@@ -16,7 +16,9 @@ var selectListTmpl string
 func usage() {
 	fmt.Println("tui-cli init <appdir>\n" +
 		"tui-cli add button <destdir>\n" +
-		"tui-cli add selectlist <destdir>")
+		"tui-cli add selectlist <destdir>\n" +
+		"tui-cli lint [-theme file] [path ...]   check class strings in Go sources\n" +
+		"tui-cli theme check [-level AA|AAA] [-all] [file]   check a theme's color contrast")
 }
 
 func ensureDir(dir string) error { return os.MkdirAll(dir, 0o755) }
@@ -88,6 +90,10 @@ func main() {
 		if err := cmdInit(os.Args[2:]); err != nil { fmt.Println("error:", err); os.Exit(1) }
 	case "add":
 		if err := cmdAdd(os.Args[2:]); err != nil { fmt.Println("error:", err); os.Exit(1) }
+	case "lint":
+		if err := cmdLint(os.Args[2:]); err != nil { fmt.Println("error:", err); os.Exit(1) }
+	case "theme":
+		if err := cmdTheme(os.Args[2:]); err != nil { fmt.Println("error:", err); os.Exit(1) }
 	default:
 		usage()
 	}
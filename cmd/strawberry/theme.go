@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/GlitchedNexus/strawberry-tui/pkg/theme"
+)
+
+// cmdTheme runs the theme subcommands; only "check" for now.
+func cmdTheme(args []string) error {
+	if len(args) == 0 || args[0] != "check" { return fmt.Errorf("usage: tui-cli theme check [-level AA|AAA] [-all] [file]") }
+	return cmdThemeCheck(args[1:])
+}
+
+// cmdThemeCheck audits the contrast of a theme file (default: the built-in
+// theme) and its variants, printing failing pairs with a suggested shade.
+// Advisory pairs (see theme.Pair) are printed but don't fail the check.
+func cmdThemeCheck(args []string) error {
+	fl := flag.NewFlagSet("theme check", flag.ContinueOnError)
+	levelName := fl.String("level", "AA", "WCAG level to require: AA or AAA")
+	all := fl.Bool("all", false, "print passing pairs too")
+	if err := fl.Parse(args); err != nil { return err }
+	var level theme.Level
+	switch strings.ToUpper(*levelName) {
+	case "AA": level = theme.AA
+	case "AAA": level = theme.AAA
+	default: return fmt.Errorf("unknown level %q (want AA or AAA)", *levelName)
+	}
+	th := theme.Default()
+	switch fl.NArg() {
+	case 0:
+	case 1:
+		var err error
+		if th, err = theme.LoadFile(fl.Arg(0)); err != nil { return err }
+	default:
+		return fmt.Errorf("theme check takes one theme file")
+	}
+
+	results := theme.Audit(th)
+	failed, advisory := 0, 0
+	for _, r := range results {
+		pass := r.Pass(level)
+		switch {
+		case pass:
+		case r.Pair.Advisory != "": advisory++
+		default: failed++
+		}
+		if pass && !*all { continue }
+		// Floor the ratio so 4.499 never reads as a passing 4.50.
+		line := fmt.Sprintf("%-24s %-20s %-9s %-12s on %-12s %5.2f:1  %s", r.Theme, r.Pair.Name, r.Profile,
+			r.FG, r.BG, math.Floor(r.Ratio*100)/100, grade(r))
+		if !pass {
+			if fix, ok := r.Suggest(level); ok {
+				line += fmt.Sprintf("  try %s", fix)
+			} else {
+				line += "  (no shade of this hue passes)"
+			}
+			if r.Pair.Advisory != "" { line += "  advisory: " + r.Pair.Advisory }
+		}
+		fmt.Println(line)
+	}
+	if failed > 0 { return fmt.Errorf("%d of %d checks fail %s", failed, len(results), level) }
+	if advisory > 0 {
+		fmt.Printf("%d checks pass %s, %d advisory ones don't\n", len(results)-advisory, level, advisory)
+		return nil
+	}
+	fmt.Printf("%d checks pass %s\n", len(results), level)
+	return nil
+}
+
+func grade(r theme.Result) string {
+	switch {
+	case r.Pass(theme.AAA) && r.Pair.Kind == theme.TextPair: return "AAA"
+	case r.Pass(theme.AA): return "AA"
+	}
+	return "fail"
+}
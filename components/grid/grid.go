@@ -1 +1,57 @@
+// Package grid arranges tiles on a CSS-grid style layout, e.g. dashboard
+// panels that stay aligned and reflow as the terminal is resized.
+//
+//	grid.New("dash", grid.Options{MinWidth: 24, Gap: 1}, cpu, mem, disk, net)
+//
+//	grid.New("app", grid.Options{
+//		Columns: []ui.Track{ui.Cells(20), ui.Fr(1)},
+//		Areas:   []string{"head head", "side main"},
+//	}, header, sidebar, body) // tiles use ui.WithArea("head") etc.
+//
+//	grid.New("list", grid.Options{Gap: 1, RowGap: grid.Int(0)}, rows...)
 package grid
+
+import "github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+
+// DefaultMinWidth is the auto-fill column width used when Options has
+// neither Columns nor MinWidth.
+const DefaultMinWidth = 20
+
+// Options describe the grid. The zero value lays tiles out in as many
+// DefaultMinWidth-or-wider columns as fit.
+type Options struct {
+	Columns  []ui.Track // explicit columns; empty means AutoFill(MinWidth)
+	Rows     []ui.Track // explicit rows; further rows are Auto
+	Areas    []string   // named areas, one string per row (see ui.WithGridAreas)
+	MinWidth int        // auto-fill column minimum
+	Gap      int        // space between rows and columns
+	RowGap   *int       // overrides Gap between rows when set, even to 0
+	ColGap   *int       // overrides Gap between columns when set, even to 0
+
+	// Extra options for the container (border, padding, size, ...).
+	Box []ui.NodeOption
+}
+
+// New returns a grid container holding tiles. Tiles may pin themselves with
+// ui.WithGridCell, ui.WithSpan or ui.WithArea; the rest fill free cells row
+// by row.
+func New(id string, o Options, tiles ...ui.Node) ui.Node {
+	cols := o.Columns
+	if len(cols) == 0 && len(o.Areas) == 0 {
+		minW := o.MinWidth
+		if minW <= 0 { minW = DefaultMinWidth }
+		cols = []ui.Track{ui.AutoFill(minW)}
+	}
+	rowGap, colGap := o.Gap, o.Gap
+	if o.RowGap != nil { rowGap = *o.RowGap }
+	if o.ColGap != nil { colGap = *o.ColGap }
+
+	opts := []ui.NodeOption{ui.WithGridGap(rowGap, colGap), ui.WithChildren(tiles...)}
+	if len(cols) > 0 { opts = append(opts, ui.WithGridColumns(cols...)) }
+	if len(o.Rows) > 0 { opts = append(opts, ui.WithGridRows(o.Rows...)) }
+	if len(o.Areas) > 0 { opts = append(opts, ui.WithGridAreas(o.Areas...)) }
+	return ui.Box(id, append(opts, o.Box...)...)
+}
+
+// Int returns a pointer to n, for the optional fields of Options.
+func Int(n int) *int { return &n }
@@ -1 +1,237 @@
 package backend
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+)
+
+// ANSI is a double-buffered backend that emits VT100/xterm escape sequences.
+//
+// Flush diffs the back buffer against the front (what the terminal shows)
+// and writes only the cursor moves, SGR changes and glyphs needed to turn one
+// into the other, which keeps redraws cheap over slow links. Frame renders
+// the whole front buffer for callers such as Bubble Tea that repaint
+// themselves.
+type ANSI struct {
+	front, back *Buffer
+	full        bool // next Flush must repaint everything
+	profile     renderer.Profile
+	stats       Stats
+}
+
+// Stats are cumulative counters for perf overlays.
+type Stats struct {
+	Frames       int
+	CellsWritten int // cells emitted by Flush
+	Bytes        int // bytes returned by Flush
+}
+
+// NewANSI returns an ANSI backend of the given size.
+func NewANSI(w, h int) *ANSI {
+	return &ANSI{front: NewBuffer(w, h), back: NewBuffer(w, h), full: true}
+}
+
+var _ Backend = (*ANSI)(nil)
+
+func (a *ANSI) Size() (w, h int) { return a.back.W, a.back.H }
+
+// Resize changes the buffer size; the next Flush repaints the whole screen.
+func (a *ANSI) Resize(w, h int) {
+	if w == a.back.W && h == a.back.H { return }
+	a.back.Resize(w, h)
+	a.front.Resize(w, h)
+	a.full = true
+}
+
+// SetProfile sets the color capability colors are downsampled to on output
+// (TrueColor by default); the next Flush repaints everything.
+func (a *ANSI) SetProfile(p renderer.Profile) {
+	if p != a.profile { a.profile, a.full = p, true }
+}
+
+// Invalidate forces the next Flush to repaint everything (e.g. after the
+// terminal was cleared behind our back).
+func (a *ANSI) Invalidate() { a.full = true }
+
+// BeginFrame starts a frame from a copy of the last flushed one.
+func (a *ANSI) BeginFrame() { a.back.CopyFrom(a.front) }
+
+func (a *ANSI) PutCell(x, y int, r rune, at Attr)      { a.back.SetRune(x, y, r, at) }
+func (a *ANSI) PutCluster(x, y int, g string, at Attr) { a.back.SetCluster(x, y, g, at) }
+
+// Cell returns the back-buffer cell at (x, y).
+func (a *ANSI) Cell(x, y int) Cell { return a.back.At(x, y) }
+
+// Stats returns cumulative counters.
+func (a *ANSI) Stats() Stats { return a.stats }
+
+// Flush returns the escape sequence that updates the terminal from the
+// previous frame to this one, then promotes the back buffer to front.
+func (a *ANSI) Flush() string {
+	var sb strings.Builder
+	w := &writer{sb: &sb, x: -1, y: -1, pen: renderer.DefaultAttr, profile: a.profile}
+	if a.full {
+		sb.WriteString("\x1b[0m\x1b[H\x1b[2J")
+		w.x, w.y = 0, 0
+	}
+	b, f := a.back, a.front
+	for y := 0; y < b.H; y++ {
+		row := b.Cells[y*b.W : (y+1)*b.W]
+		old := f.Cells[y*f.W : (y+1)*f.W]
+		// After a clear the terminal shows blanks, not the old front buffer.
+		same := func(x int) bool {
+			if a.full { return row[x] == Blank }
+			return row[x] == old[x]
+		}
+		for x := 0; x < b.W; {
+			if same(x) { x++; continue }
+			// Extend the run over changed cells, bridging short unchanged gaps
+			// where re-sending the glyphs is cheaper than a cursor jump.
+			end := x + 1
+			for end < b.W {
+				if !same(end) { end++; continue }
+				gap := end
+				for gap < b.W && gap-end < maxBridge && same(gap) { gap++ }
+				if gap < b.W && gap-end < maxBridge { end = gap + 1; continue }
+				break
+			}
+			// A run must not start on the right half of a wide cluster.
+			if row[x].W == 0 && x > 0 { x-- }
+			w.move(x, y)
+			for ; x < end; x++ {
+				c := row[x]
+				if c.W == 0 { continue }
+				w.sgr(c.A)
+				sb.WriteString(c.Text())
+				w.x += int(c.W)
+				a.stats.CellsWritten++
+			}
+		}
+	}
+	if w.pen != renderer.DefaultAttr { sb.WriteString("\x1b[0m") }
+	a.front.CopyFrom(a.back)
+	a.full = false
+	a.stats.Frames++
+	a.stats.Bytes += sb.Len()
+	return sb.String()
+}
+
+// Frame renders the front buffer as newline-separated rows, each ending with
+// an SGR reset, suitable as a Bubble Tea View() result.
+func (a *ANSI) Frame() string { return Render(a.front, a.profile) }
+
+// Render returns buf as newline-separated rows of styled text, with colors
+// downsampled to p.
+func Render(buf *Buffer, p renderer.Profile) string {
+	var sb strings.Builder
+	for y := 0; y < buf.H; y++ {
+		if y > 0 { sb.WriteByte('\n') }
+		w := &writer{sb: &sb, pen: renderer.DefaultAttr, profile: p}
+		for _, c := range buf.Cells[y*buf.W : (y+1)*buf.W] {
+			if c.W == 0 { continue }
+			w.sgr(c.A)
+			sb.WriteString(c.Text())
+		}
+		if w.pen != renderer.DefaultAttr { sb.WriteString("\x1b[0m") }
+	}
+	return sb.String()
+}
+
+// maxBridge is the longest unchanged gap re-sent instead of moving the cursor.
+const maxBridge = 4
+
+// writer tracks the terminal cursor and pen so it can emit minimal sequences.
+type writer struct {
+	sb      *strings.Builder
+	x, y    int // cursor position, -1 when unknown
+	pen     Attr
+	profile renderer.Profile
+}
+
+func (w *writer) move(x, y int) {
+	switch {
+	case x == w.x && y == w.y:
+		return
+	case y == w.y && x > w.x && w.x >= 0:
+		w.csi(x-w.x, 'C') // cursor forward
+	case y == w.y+1 && x == 0 && w.y >= 0:
+		w.sb.WriteString("\r\n")
+	default:
+		w.sb.WriteString("\x1b[" + strconv.Itoa(y+1) + ";" + strconv.Itoa(x+1) + "H")
+	}
+	w.x, w.y = x, y
+}
+
+func (w *writer) csi(n int, cmd byte) {
+	w.sb.WriteString("\x1b[")
+	if n != 1 { w.sb.WriteString(strconv.Itoa(n)) }
+	w.sb.WriteByte(cmd)
+}
+
+// sgr switches the pen to a, resetting first only when an attribute has to
+// be turned off (there is no portable "bold off" that leaves dim alone).
+func (w *writer) sgr(a Attr) {
+	a.FG, a.BG = a.FG.Downsample(w.profile), a.BG.Downsample(w.profile)
+	a.UnderlineColor = a.UnderlineColor.Downsample(w.profile)
+	if !a.Underline { a.UnderlineStyle, a.UnderlineColor = 0, 0 }
+	if a == w.pen { return }
+	var codes []string
+	from := w.pen
+	if turnsOff(from, a) {
+		codes = append(codes, "0")
+		from = renderer.DefaultAttr
+	}
+	flag := func(on, was bool, code string) {
+		if on && !was { codes = append(codes, code) }
+	}
+	flag(a.Bold, from.Bold, "1")
+	flag(a.Dim, from.Dim, "2")
+	flag(a.Italic, from.Italic, "3")
+	flag(a.Blink, from.Blink, "5")
+	flag(a.Reverse, from.Reverse, "7")
+	flag(a.Strikethrough, from.Strikethrough, "9")
+	if a.Underline && (!from.Underline || a.UnderlineStyle != from.UnderlineStyle) {
+		if a.UnderlineStyle == renderer.UnderlineSingle { codes = append(codes, "4") } else { codes = append(codes, "4:"+strconv.Itoa(int(a.UnderlineStyle)+1)) }
+	}
+	if a.UnderlineColor != from.UnderlineColor {
+		if a.UnderlineColor == renderer.DefaultColor { codes = append(codes, "59") } else { codes = append(codes, underlineColorCode(a.UnderlineColor)) }
+	}
+	if a.FG != from.FG { codes = append(codes, colorCode(a.FG, false)) }
+	if a.BG != from.BG { codes = append(codes, colorCode(a.BG, true)) }
+	w.sb.WriteString("\x1b[" + strings.Join(codes, ";") + "m")
+	w.pen = a
+}
+
+// turnsOff reports whether moving from one pen to the next clears a flag.
+func turnsOff(from, to Attr) bool {
+	return (from.Bold && !to.Bold) || (from.Dim && !to.Dim) || (from.Italic && !to.Italic) ||
+		(from.Blink && !to.Blink) || (from.Reverse && !to.Reverse) ||
+		(from.Strikethrough && !to.Strikethrough) || (from.Underline && !to.Underline)
+}
+
+// underlineColorCode uses SGR 58, which has no 16-color form; basic colors
+// go through the 256-color index they share.
+func underlineColorCode(c renderer.Color) string {
+	if c.Kind() == renderer.KindRGB {
+		r, g, b, _ := c.RGB()
+		return "58;2;" + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b))
+	}
+	return "58;5;" + strconv.Itoa(c.Index())
+}
+
+func colorCode(c renderer.Color, bg bool) string {
+	base := 30
+	if bg { base = 40 }
+	switch c.Kind() {
+	case renderer.KindANSI:
+		if i := c.Index(); i < 8 { return strconv.Itoa(base + i) } else { return strconv.Itoa(base + 60 + i - 8) }
+	case renderer.KindIndexed:
+		return strconv.Itoa(base+8) + ";5;" + strconv.Itoa(c.Index())
+	case renderer.KindRGB:
+		r, g, b, _ := c.RGB()
+		return strconv.Itoa(base+8) + ";2;" + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b))
+	}
+	return strconv.Itoa(base + 9)
+}
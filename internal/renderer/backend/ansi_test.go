@@ -0,0 +1,102 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+)
+
+// frame writes rows (one string per row, ' ' for blank) into a new frame.
+func frame(b Backend, rows ...string) {
+	b.BeginFrame()
+	for y, row := range rows {
+		for x, r := range []rune(row) { b.PutCell(x, y, r, Attr{}) }
+	}
+}
+
+func TestFlushOnlyChangedCells(t *testing.T) {
+	bold := Attr{Bold: true}
+	tests := []struct {
+		name  string
+		next  func(a *ANSI)
+		want  string
+		cells int
+	}{
+		{"unchanged", func(a *ANSI) { frame(a, "hello", "world") }, "", 0},
+		{"one cell", func(a *ANSI) { frame(a, "hello", "wOrld") }, "\x1b[2;2HO", 1},
+		{"run", func(a *ANSI) { frame(a, "HELlo", "world") }, "\x1b[1;1HHEL", 3},
+		{"short gap bridged", func(a *ANSI) { frame(a, "HellO", "world") }, "\x1b[1;1HHellO", 5},
+		{"two rows", func(a *ANSI) { frame(a, "Hello", "World") }, "\x1b[1;1HH\r\nW", 2},
+		{"attr only", func(a *ANSI) { frame(a, "hello", "world"); a.PutCell(4, 1, 'd', bold) }, "\x1b[2;5H\x1b[1md\x1b[0m", 1},
+		{"wide cluster", func(a *ANSI) { frame(a, "hello", "world"); a.PutCluster(1, 0, "日", Attr{}) }, "\x1b[1;2H日", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewANSI(5, 2)
+			frame(a, "hello", "world")
+			a.Flush()
+			before := a.Stats().CellsWritten
+			tt.next(a)
+			if got := a.Flush(); got != tt.want { t.Errorf("Flush = %q, want %q", got, tt.want) }
+			if n := a.Stats().CellsWritten - before; n != tt.cells { t.Errorf("wrote %d cells, want %d", n, tt.cells) }
+		})
+	}
+}
+
+func TestFlushLongGapMovesCursor(t *testing.T) {
+	a := NewANSI(12, 1)
+	frame(a, "abcdefghijkl")
+	a.Flush()
+	frame(a, "Abcdefghijkl")
+	a.PutCell(11, 0, 'L', Attr{})
+	if got, want := a.Flush(), "\x1b[1;1HA\x1b[10CL"; got != want { t.Errorf("Flush = %q, want %q", got, want) }
+}
+
+func TestFlushFullRepaint(t *testing.T) {
+	a := NewANSI(3, 1)
+	frame(a, "ab ")
+	if got, want := a.Flush(), "\x1b[0m\x1b[H\x1b[2Jab"; got != want { t.Errorf("first Flush = %q, want %q", got, want) }
+	a.Invalidate()
+	frame(a, "ab ")
+	if got, want := a.Flush(), "\x1b[0m\x1b[H\x1b[2Jab"; got != want { t.Errorf("Flush after Invalidate = %q, want %q", got, want) }
+	a.SetProfile(renderer.ANSI256)
+	frame(a, "ab ")
+	if got := a.Flush(); got == "" { t.Error("profile change did not repaint") }
+}
+
+func TestFlushSGR(t *testing.T) {
+	ul := func(s renderer.UnderlineStyle, c renderer.Color) Attr { return Attr{Underline: true, UnderlineStyle: s, UnderlineColor: c} }
+	tests := []struct {
+		name  string
+		attrs []Attr // one cell each, left to right
+		want  string // output after moving to the first cell
+	}{
+		{"italic dim", []Attr{{Italic: true, Dim: true}}, "\x1b[2;3mx\x1b[0m"},
+		{"strikethrough", []Attr{{Strikethrough: true}}, "\x1b[9mx\x1b[0m"},
+		{"blink", []Attr{{Blink: true}}, "\x1b[5mx\x1b[0m"},
+		{"reverse", []Attr{{Reverse: true}}, "\x1b[7mx\x1b[0m"},
+		{"underline", []Attr{{Underline: true}}, "\x1b[4mx\x1b[0m"},
+		{"double underline", []Attr{ul(renderer.UnderlineDouble, 0)}, "\x1b[4:2mx\x1b[0m"},
+		{"curly underline", []Attr{ul(renderer.UnderlineCurly, 0)}, "\x1b[4:3mx\x1b[0m"},
+		{"dotted underline", []Attr{ul(renderer.UnderlineDotted, 0)}, "\x1b[4:4mx\x1b[0m"},
+		{"dashed underline", []Attr{ul(renderer.UnderlineDashed, 0)}, "\x1b[4:5mx\x1b[0m"},
+		{"underline rgb color", []Attr{ul(renderer.UnderlineSingle, renderer.RGB(1, 2, 3))}, "\x1b[4;58;2;1;2;3mx\x1b[0m"},
+		{"underline basic color", []Attr{ul(renderer.UnderlineCurly, renderer.ANSI(9))}, "\x1b[4:3;58;5;9mx\x1b[0m"},
+		{"style without underline", []Attr{{UnderlineStyle: renderer.UnderlineCurly, UnderlineColor: renderer.ANSI(1)}}, "x"},
+		{"add a flag", []Attr{{Italic: true}, {Italic: true, Strikethrough: true}}, "\x1b[3mx\x1b[9mx\x1b[0m"},
+		{"drop a flag resets", []Attr{{Italic: true, Blink: true}, {Italic: true}}, "\x1b[3;5mx\x1b[0;3mx\x1b[0m"},
+		{"change underline style", []Attr{ul(renderer.UnderlineSingle, 0), ul(renderer.UnderlineDashed, 0)}, "\x1b[4mx\x1b[4:5mx\x1b[0m"},
+		{"drop underline color", []Attr{ul(renderer.UnderlineSingle, renderer.ANSI(2)), ul(renderer.UnderlineSingle, 0)}, "\x1b[4;58;5;2mx\x1b[59mx\x1b[0m"},
+		{"drop underline", []Attr{ul(renderer.UnderlineCurly, renderer.ANSI(2)), {}}, "\x1b[4:3;58;5;2mx\x1b[0mx"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewANSI(len(tt.attrs), 1)
+			a.SetProfile(renderer.TrueColor)
+			a.Flush()
+			a.BeginFrame()
+			for x, at := range tt.attrs { a.PutCell(x, 0, 'x', at) }
+			if got, want := a.Flush(), "\x1b[1;1H"+tt.want; got != want { t.Errorf("Flush = %q, want %q", got, want) }
+		})
+	}
+}
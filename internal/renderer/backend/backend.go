@@ -0,0 +1,126 @@
+// Package backend turns cell writes into terminal output.
+package backend
+
+import (
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+	"github.com/rivo/uniseg"
+)
+
+type Attr = renderer.Attr
+
+// Backend receives the cells of a frame and commits them.
+//
+// Cells written between BeginFrame and Flush land in a back buffer that
+// starts as a copy of the last flushed frame, so a frame only needs to
+// write the cells that changed.
+type Backend interface {
+	Size() (w, h int)
+	Resize(w, h int)
+	BeginFrame()
+	PutCell(x, y int, r rune, a Attr)
+	// PutCluster writes a grapheme cluster (base rune plus combining marks or
+	// a ZWJ sequence) that may span two columns.
+	PutCluster(x, y int, g string, a Attr)
+	Flush() string
+}
+
+// Cell is one terminal cell. A wide cluster occupies its lead cell (W == 2)
+// and a continuation cell (W == 0) immediately to the right.
+type Cell struct {
+	R rune   // first rune of the cluster
+	G string // full cluster when it is more than R alone
+	W int8   // display width: 1, 2, or 0 for a continuation
+	A Attr
+}
+
+// Blank is an empty cell in terminal colors.
+var Blank = Cell{R: ' ', W: 1, A: renderer.DefaultAttr}
+
+// Text returns the cell content ("" for continuation cells).
+func (c Cell) Text() string {
+	switch {
+	case c.W == 0: return ""
+	case c.G != "": return c.G
+	}
+	return string(c.R)
+}
+
+// Buffer is a W×H grid of cells in row-major order.
+type Buffer struct {
+	W, H  int
+	Cells []Cell
+}
+
+// NewBuffer returns a blank buffer.
+func NewBuffer(w, h int) *Buffer {
+	b := &Buffer{W: max(w, 0), H: max(h, 0)}
+	b.Cells = make([]Cell, b.W*b.H)
+	b.Clear()
+	return b
+}
+
+// Clear blanks every cell.
+func (b *Buffer) Clear() {
+	for i := range b.Cells { b.Cells[i] = Blank }
+}
+
+// At returns the cell at (x, y); out-of-range reads return Blank.
+func (b *Buffer) At(x, y int) Cell {
+	if x < 0 || y < 0 || x >= b.W || y >= b.H { return Blank }
+	return b.Cells[y*b.W+x]
+}
+
+// CopyFrom makes b an exact copy of o (resizing if needed).
+func (b *Buffer) CopyFrom(o *Buffer) {
+	b.W, b.H = o.W, o.H
+	if cap(b.Cells) < len(o.Cells) { b.Cells = make([]Cell, len(o.Cells)) }
+	b.Cells = b.Cells[:len(o.Cells)]
+	copy(b.Cells, o.Cells)
+}
+
+// Resize keeps the overlapping top-left region and blanks the rest.
+func (b *Buffer) Resize(w, h int) {
+	nb := NewBuffer(w, h)
+	for y := 0; y < min(h, b.H); y++ {
+		for x := 0; x < min(w, b.W); x++ { nb.Cells[y*nb.W+x] = b.Cells[y*b.W+x] }
+	}
+	// A wide cluster cut in half by the new right edge is dropped.
+	for y := 0; y < nb.H && nb.W > 0; y++ {
+		if c := &nb.Cells[y*nb.W+nb.W-1]; c.W == 2 { *c = Blank }
+	}
+	*b = *nb
+}
+
+// SetRune writes a single-rune cell.
+func (b *Buffer) SetRune(x, y int, r rune, a Attr) {
+	w := 1
+	if r < 0x20 || r >= 0x7f { w = uniseg.StringWidth(string(r)) }
+	b.set(x, y, Cell{R: r, W: int8(w), A: a})
+}
+
+// SetCluster writes a grapheme cluster; zero-width clusters are ignored.
+func (b *Buffer) SetCluster(x, y int, g string, a Attr) {
+	if g == "" { return }
+	w := uniseg.StringWidth(g)
+	c := Cell{W: int8(min(w, 2)), A: a}
+	for i, r := range g {
+		if i == 0 { c.R = r } else { c.G = g; break }
+	}
+	b.set(x, y, c)
+}
+
+func (b *Buffer) set(x, y int, c Cell) {
+	if c.W <= 0 || x < 0 || y < 0 || x >= b.W || y >= b.H { return }
+	if c.W == 2 && x+1 >= b.W {
+		c = Cell{R: ' ', W: 1, A: c.A} // no room for the right half
+	}
+	i := y*b.W + x
+	// Overwriting either half of a wide cluster erases the other half.
+	if b.Cells[i].W == 0 && x > 0 { b.Cells[i-1] = Cell{R: ' ', W: 1, A: b.Cells[i-1].A} }
+	if b.Cells[i].W == 2 && x+1 < b.W { b.Cells[i+1] = Cell{R: ' ', W: 1, A: b.Cells[i+1].A} }
+	b.Cells[i] = c
+	if c.W == 2 {
+		if b.Cells[i+1].W == 2 && x+2 < b.W { b.Cells[i+2] = Cell{R: ' ', W: 1, A: b.Cells[i+2].A} }
+		b.Cells[i+1] = Cell{W: 0, A: c.A}
+	}
+}
@@ -1 +1,99 @@
-package backend
\ No newline at end of file
+package backend
+
+import (
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+)
+
+// Tcell is a double-buffered backend drawing onto a tcell.Screen, for
+// programs that own the terminal (or a tcell.SimulationScreen in tests)
+// rather than returning frames to Bubble Tea.
+//
+// Flush hands tcell only the cells that changed since the last frame and
+// calls Show; tcell then does its own terminal diffing, color downsampling
+// via terminfo, and input decoding (read events with Screen().PollEvent).
+// The caller owns the screen: Init it before NewTcell and Fini it when done.
+type Tcell struct {
+	screen      tcell.Screen
+	front, back *Buffer
+	full        bool // next Flush must resend everything and Sync
+	styles      map[Attr]tcell.Style
+}
+
+// NewTcell returns a backend sized to s.
+func NewTcell(s tcell.Screen) *Tcell {
+	w, h := s.Size()
+	return &Tcell{screen: s, front: NewBuffer(w, h), back: NewBuffer(w, h), full: true, styles: map[Attr]tcell.Style{}}
+}
+
+var _ Backend = (*Tcell)(nil)
+
+// Screen returns the underlying screen, e.g. to poll input events.
+func (t *Tcell) Screen() tcell.Screen { return t.screen }
+
+func (t *Tcell) Size() (w, h int) { return t.back.W, t.back.H }
+
+// Resize changes the buffer size (call it on *tcell.EventResize); the next
+// Flush repaints everything.
+func (t *Tcell) Resize(w, h int) {
+	if w == t.back.W && h == t.back.H { return }
+	t.back.Resize(w, h)
+	t.front.Resize(w, h)
+	t.full = true
+}
+
+// Invalidate forces the next Flush to repaint everything.
+func (t *Tcell) Invalidate() { t.full = true }
+
+// BeginFrame starts a frame from a copy of the last flushed one.
+func (t *Tcell) BeginFrame() { t.back.CopyFrom(t.front) }
+
+func (t *Tcell) PutCell(x, y int, r rune, a Attr)      { t.back.SetRune(x, y, r, a) }
+func (t *Tcell) PutCluster(x, y int, g string, a Attr) { t.back.SetCluster(x, y, g, a) }
+
+// Cell returns the back-buffer cell at (x, y).
+func (t *Tcell) Cell(x, y int) Cell { return t.back.At(x, y) }
+
+// Flush sends the changed cells to the screen and shows them. The returned
+// string is always empty: output goes straight to the terminal.
+func (t *Tcell) Flush() string {
+	if t.full { t.screen.Clear() }
+	b, f := t.back, t.front
+	for i, c := range b.Cells {
+		if c.W == 0 || (!t.full && c == f.Cells[i]) { continue }
+		var rest []rune
+		r := c.R
+		if c.G != "" {
+			rs := []rune(c.G)
+			r, rest = rs[0], rs[1:]
+		}
+		t.screen.SetContent(i%b.W, i/b.W, r, rest, t.style(c.A))
+	}
+	if t.full { t.screen.Sync() } else { t.screen.Show() }
+	t.front.CopyFrom(t.back)
+	t.full = false
+	return ""
+}
+
+// style converts a to a tcell style, caching the result.
+func (t *Tcell) style(a Attr) tcell.Style {
+	if s, ok := t.styles[a]; ok { return s }
+	s := tcell.StyleDefault.Foreground(tcellColor(a.FG)).Background(tcellColor(a.BG)).
+		Bold(a.Bold).Dim(a.Dim).Italic(a.Italic).Blink(a.Blink).Reverse(a.Reverse).StrikeThrough(a.Strikethrough)
+	if a.Underline { s = s.Underline(tcell.UnderlineStyleSolid+tcell.UnderlineStyle(a.UnderlineStyle), tcellColor(a.UnderlineColor)) }
+	if len(t.styles) > 1024 { clear(t.styles) }
+	t.styles[a] = s
+	return s
+}
+
+func tcellColor(c renderer.Color) tcell.Color {
+	switch c.Kind() {
+	case renderer.KindANSI, renderer.KindIndexed:
+		return tcell.PaletteColor(c.Index())
+	case renderer.KindRGB:
+		r, g, b, _ := c.RGB()
+		return tcell.NewRGBColor(int32(r), int32(g), int32(b))
+	}
+	return tcell.ColorDefault
+}
@@ -0,0 +1,141 @@
+package backend
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+)
+
+// recordingScreen is a simulation screen that logs the cells it is sent.
+type recordingScreen struct {
+	tcell.SimulationScreen
+	sent         []string // "x,y=r"
+	shows, syncs int
+}
+
+func (s *recordingScreen) SetContent(x, y int, r rune, comb []rune, st tcell.Style) {
+	s.sent = append(s.sent, fmt.Sprintf("%d,%d=%c", x, y, r))
+	s.SimulationScreen.SetContent(x, y, r, comb, st)
+}
+
+func (s *recordingScreen) Show() { s.shows++; s.SimulationScreen.Show() }
+func (s *recordingScreen) Sync() { s.syncs++; s.SimulationScreen.Sync() }
+
+func newSimScreen(t *testing.T, w, h int) *recordingScreen {
+	t.Helper()
+	sim := tcell.NewSimulationScreen("")
+	if err := sim.Init(); err != nil { t.Fatal(err) }
+	t.Cleanup(sim.Fini)
+	sim.SetSize(w, h)
+	return &recordingScreen{SimulationScreen: sim}
+}
+
+// row returns line y of the simulated terminal.
+func row(s tcell.SimulationScreen, y int) string {
+	cells, w, _ := s.GetContents()
+	var out []rune
+	for _, c := range cells[y*w : (y+1)*w] { out = append(out, c.Runes...) }
+	return string(out)
+}
+
+func TestTcellFlushOnlyChangedCells(t *testing.T) {
+	tests := []struct {
+		name string
+		next func(b *Tcell)
+		sent []string
+	}{
+		{"unchanged", func(b *Tcell) { frame(b, "hello", "world") }, nil},
+		{"one cell", func(b *Tcell) { frame(b, "hello", "wOrld") }, []string{"1,1=O"}},
+		{"two rows", func(b *Tcell) { frame(b, "Hello", "World") }, []string{"0,0=H", "0,1=W"}},
+		{"attr only", func(b *Tcell) { frame(b, "hello", "world"); b.PutCell(4, 1, 'd', Attr{Bold: true}) }, []string{"4,1=d"}},
+		{"wide cluster", func(b *Tcell) { frame(b, "hello", "world"); b.PutCluster(1, 0, "日", Attr{}) }, []string{"1,0=日"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSimScreen(t, 5, 2)
+			b := NewTcell(s)
+			frame(b, "hello", "world")
+			b.Flush()
+			if s.syncs != 1 || s.shows != 0 { t.Errorf("first Flush: %d syncs, %d shows, want a single Sync", s.syncs, s.shows) }
+			s.sent = nil
+			tt.next(b)
+			if got := b.Flush(); got != "" { t.Errorf("Flush = %q, want \"\"", got) }
+			if !reflect.DeepEqual(s.sent, tt.sent) { t.Errorf("sent %v, want %v", s.sent, tt.sent) }
+			if s.shows != 1 { t.Errorf("%d shows, want 1", s.shows) }
+		})
+	}
+}
+
+func TestTcellScreenContents(t *testing.T) {
+	s := newSimScreen(t, 5, 2)
+	b := NewTcell(s)
+	frame(b, "hello", "world")
+	b.PutCluster(0, 1, "日", Attr{})
+	b.Flush()
+	if got := row(s, 0); got != "hello" { t.Errorf("row 0 = %q", got) }
+	// The simulation keeps its fill char under a wide cluster's right half.
+	cells, w, _ := s.GetContents()
+	if got := string(cells[w].Runes); got != "日" { t.Errorf("cell (0,1) = %q, want 日", got) }
+	if got := row(s, 1)[len("日")+1:]; got != "rld" { t.Errorf("row 1 after the wide cluster = %q, want rld", got) }
+}
+
+func TestTcellInvalidateAndResize(t *testing.T) {
+	s := newSimScreen(t, 3, 1)
+	b := NewTcell(s)
+	frame(b, "abc")
+	b.Flush()
+
+	s.sent = nil
+	b.Invalidate()
+	frame(b, "abc")
+	b.Flush()
+	if len(s.sent) != 3 || s.syncs != 2 { t.Errorf("after Invalidate sent %v with %d syncs, want every cell and a Sync", s.sent, s.syncs) }
+
+	s.sent = nil
+	s.SetSize(4, 1)
+	b.Resize(4, 1)
+	frame(b, "abcd")
+	b.Flush()
+	if w, h := b.Size(); w != 4 || h != 1 { t.Errorf("Size = %dx%d, want 4x1", w, h) }
+	if len(s.sent) != 4 || s.syncs != 3 { t.Errorf("after Resize sent %v with %d syncs, want every cell and a Sync", s.sent, s.syncs) }
+	if got := row(s, 0); got != "abcd" { t.Errorf("row 0 = %q", got) }
+}
+
+func TestTcellStyle(t *testing.T) {
+	red := renderer.ANSI(1)
+	def := tcell.StyleDefault.Foreground(tcell.ColorDefault).Background(tcell.ColorDefault)
+	tests := []struct {
+		name string
+		a    Attr
+		want tcell.Style
+	}{
+		{"default", Attr{}, def},
+		{"ansi", Attr{FG: red, BG: renderer.Indexed(200)}, tcell.StyleDefault.Foreground(tcell.PaletteColor(1)).Background(tcell.PaletteColor(200))},
+		{"rgb", Attr{FG: renderer.RGB(0x12, 0x34, 0x56)}, def.Foreground(tcell.NewRGBColor(0x12, 0x34, 0x56))},
+		{"flags", Attr{Bold: true, Dim: true, Italic: true, Blink: true, Reverse: true, Strikethrough: true},
+			def.Bold(true).Dim(true).Italic(true).Blink(true).Reverse(true).StrikeThrough(true)},
+		{"underline", Attr{Underline: true}, def.Underline(tcell.UnderlineStyleSolid, tcell.ColorDefault)},
+		{"double underline", Attr{Underline: true, UnderlineStyle: renderer.UnderlineDouble}, def.Underline(tcell.UnderlineStyleDouble, tcell.ColorDefault)},
+		{"curly colored", Attr{Underline: true, UnderlineStyle: renderer.UnderlineCurly, UnderlineColor: red}, def.Underline(tcell.UnderlineStyleCurly, tcell.PaletteColor(1))},
+		{"dotted", Attr{Underline: true, UnderlineStyle: renderer.UnderlineDotted}, def.Underline(tcell.UnderlineStyleDotted, tcell.ColorDefault)},
+		{"dashed", Attr{Underline: true, UnderlineStyle: renderer.UnderlineDashed}, def.Underline(tcell.UnderlineStyleDashed, tcell.ColorDefault)},
+		{"style without underline", Attr{UnderlineStyle: renderer.UnderlineCurly, UnderlineColor: red}, def},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSimScreen(t, 1, 1)
+			b := NewTcell(s)
+			if got := b.style(tt.a); got != tt.want { t.Errorf("style = %+v, want %+v", got, tt.want) }
+			// And what actually reaches the screen.
+			b.BeginFrame()
+			b.PutCell(0, 0, 'x', tt.a)
+			b.Flush()
+			cells, _, _ := s.GetContents()
+			if cells[0].Style != tt.want { t.Errorf("screen style = %+v, want %+v", cells[0].Style, tt.want) }
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package renderer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Color is a terminal color: the terminal's default, one of the 16 ANSI
+// colors, an xterm 256-color index, or 24-bit RGB. The zero value is the
+// default color, so Attr{Bold: true} keeps the terminal's colors.
+//
+// Layout: the top byte holds the ColorKind, the low 24 bits the index or RGB.
+type Color uint32
+
+// ColorKind tells how a Color is expressed.
+type ColorKind uint8
+
+const (
+	KindDefault ColorKind = iota
+	KindANSI              // 0–15
+	KindIndexed           // 0–255 (xterm palette)
+	KindRGB               // 24-bit
+)
+
+// DefaultColor leaves the terminal's own foreground/background in place.
+const DefaultColor Color = 0
+
+// ANSI returns one of the 16 basic colors (0–7 normal, 8–15 bright).
+func ANSI(i uint8) Color { return Color(KindANSI)<<24 | Color(i&15) }
+
+// Indexed returns an xterm 256-color palette entry.
+func Indexed(i uint8) Color { return Color(KindIndexed)<<24 | Color(i) }
+
+// RGB returns a 24-bit color.
+func RGB(r, g, b uint8) Color { return Color(KindRGB)<<24 | Color(r)<<16 | Color(g)<<8 | Color(b) }
+
+// Hex parses "#RRGGBB" or "#RGB". The '#' is required, so token names
+// that happen to be hex digits ("fed", "decade") are not colors.
+func Hex(s string) (Color, error) {
+	h, ok := strings.CutPrefix(s, "#")
+	if !ok { return DefaultColor, fmt.Errorf("renderer: invalid hex color %q", s) }
+	if len(h) == 3 { h = string([]byte{h[0], h[0], h[1], h[1], h[2], h[2]}) }
+	if len(h) != 6 { return DefaultColor, fmt.Errorf("renderer: invalid hex color %q", s) }
+	v, err := strconv.ParseUint(h, 16, 32)
+	if err != nil { return DefaultColor, fmt.Errorf("renderer: invalid hex color %q", s) }
+	return RGB(uint8(v>>16), uint8(v>>8), uint8(v)), nil
+}
+
+// Kind reports how c is expressed.
+func (c Color) Kind() ColorKind { return ColorKind(c >> 24) }
+
+// Index returns the palette index of an ANSI or Indexed color (-1 otherwise).
+func (c Color) Index() int {
+	switch c.Kind() {
+	case KindANSI, KindIndexed: return int(c & 0xff)
+	}
+	return -1
+}
+
+// RGB returns the color's red, green and blue components; palette colors
+// use the xterm defaults. ok is false for the default color.
+func (c Color) RGB() (r, g, b uint8, ok bool) {
+	switch c.Kind() {
+	case KindRGB:
+		return uint8(c >> 16), uint8(c >> 8), uint8(c), true
+	case KindANSI, KindIndexed:
+		v := palette256[c&0xff]
+		return uint8(v >> 16), uint8(v >> 8), uint8(v), true
+	}
+	return 0, 0, 0, false
+}
+
+func (c Color) String() string {
+	switch c.Kind() {
+	case KindANSI: return "ansi(" + strconv.Itoa(c.Index()) + ")"
+	case KindIndexed: return "indexed(" + strconv.Itoa(c.Index()) + ")"
+	case KindRGB:
+		r, g, b, _ := c.RGB()
+		return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	}
+	return "default"
+}
+
+// Profile is a terminal's color capability.
+type Profile int
+
+const (
+	TrueColor Profile = iota // 24-bit; colors pass through
+	ANSI256                  // xterm 256-color palette
+	ANSI16                   // 16 basic colors
+	NoColor                  // monochrome; every color becomes the default
+)
+
+func (p Profile) String() string {
+	switch p {
+	case TrueColor: return "truecolor"
+	case ANSI256: return "ansi256"
+	case ANSI16: return "ansi16"
+	case NoColor: return "nocolor"
+	}
+	return "profile(" + strconv.Itoa(int(p)) + ")"
+}
+
+// Downsample converts c to the richest form p can display, picking the
+// perceptually nearest palette entry (Euclidean distance in OKLab).
+func (c Color) Downsample(p Profile) Color {
+	switch {
+	case c == DefaultColor || p == TrueColor:
+		return c
+	case p == NoColor:
+		return DefaultColor
+	case c.Kind() == KindANSI:
+		return c
+	case p == ANSI256 && c.Kind() == KindIndexed:
+		return c
+	}
+	key := downKey{c, p}
+	downMu.Lock()
+	v, ok := downCache[key]
+	downMu.Unlock()
+	if ok { return v }
+
+	r, g, b, _ := c.RGB()
+	if p == ANSI256 {
+		v = Indexed(uint8(nearest(r, g, b, 16, 256)))
+	} else {
+		v = ANSI(uint8(nearest(r, g, b, 0, 16)))
+	}
+	downMu.Lock()
+	if len(downCache) > 4096 { downCache = map[downKey]Color{} }
+	downCache[key] = v
+	downMu.Unlock()
+	return v
+}
+
+type downKey struct {
+	c Color
+	p Profile
+}
+
+var (
+	downMu    sync.Mutex
+	downCache = map[downKey]Color{}
+)
+
+// nearest returns the palette index in [lo, hi) closest to r, g, b. Palette
+// entries 0–15 are excluded when targeting 256 colors because terminals
+// theme them freely; the cube and gray ramp are fixed.
+func nearest(r, g, b uint8, lo, hi int) int {
+	L, A, B := OKLab(r, g, b)
+	best, bestD := lo, math.MaxFloat64
+	for i := lo; i < hi; i++ {
+		d := (L-paletteLab[i][0])*(L-paletteLab[i][0]) + (A-paletteLab[i][1])*(A-paletteLab[i][1]) + (B-paletteLab[i][2])*(B-paletteLab[i][2])
+		if d < bestD { best, bestD = i, d }
+	}
+	return best
+}
+
+// OKLab converts sRGB to Björn Ottosson's OKLab (L in [0,1]).
+func OKLab(r, g, b uint8) (L, A, B float64) {
+	lr, lg, lb := linear(r), linear(g), linear(b)
+	l := math.Cbrt(0.4122214708*lr + 0.5363325363*lg + 0.0514459929*lb)
+	m := math.Cbrt(0.2119034982*lr + 0.6806995451*lg + 0.1073969566*lb)
+	s := math.Cbrt(0.0883024619*lr + 0.2817188376*lg + 0.6299787005*lb)
+	return 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		0.0259040371*l + 0.7827717662*m - 0.8086757660*s
+}
+
+// FromOKLab converts OKLab back to sRGB, clamping out-of-gamut values.
+func FromOKLab(L, A, B float64) (r, g, b uint8) {
+	lr, lg, lb := okLabLinear(L, A, B)
+	return unlinear(lr), unlinear(lg), unlinear(lb)
+}
+
+// OKLCH converts sRGB to OKLab's polar form: lightness, chroma and hue
+// (radians).
+func OKLCH(r, g, b uint8) (L, C, H float64) {
+	L, A, B := OKLab(r, g, b)
+	return L, math.Hypot(A, B), math.Atan2(B, A)
+}
+
+// FromOKLCH converts OKLCH to sRGB. Out-of-gamut colors lose chroma until
+// they fit, so lightness and hue are kept rather than clipped.
+func FromOKLCH(L, C, H float64) (r, g, b uint8) {
+	fits := func(c float64) bool {
+		lr, lg, lb := okLabLinear(L, c*math.Cos(H), c*math.Sin(H))
+		const eps = 1e-6
+		return lr >= -eps && lr <= 1+eps && lg >= -eps && lg <= 1+eps && lb >= -eps && lb <= 1+eps
+	}
+	if !fits(C) {
+		lo, hi := 0.0, C
+		for i := 0; i < 24; i++ {
+			if mid := (lo + hi) / 2; fits(mid) { lo = mid } else { hi = mid }
+		}
+		C = lo
+	}
+	return FromOKLab(L, C*math.Cos(H), C*math.Sin(H))
+}
+
+// okLabLinear converts OKLab to linear sRGB, unclamped.
+func okLabLinear(L, A, B float64) (r, g, b float64) {
+	l := L + 0.3963377774*A + 0.2158037573*B
+	m := L - 0.1055613458*A - 0.0638541728*B
+	s := L - 0.0894841775*A - 1.2914855480*B
+	l, m, s = l*l*l, m*m*m, s*s*s
+	return +4.0767416621*l - 3.3077115913*m + 0.2309699292*s,
+		-1.2684380046*l + 2.6097574011*m - 0.3413193965*s,
+		-0.0041960863*l - 0.7034186147*m + 1.7076147010*s
+}
+
+// Luminance is c's WCAG 2 relative luminance, from 0 (black) to 1 (white);
+// palette colors use the xterm defaults and the default color counts as black.
+func (c Color) Luminance() float64 {
+	r, g, b, _ := c.RGB()
+	return 0.2126*linear(r) + 0.7152*linear(g) + 0.0722*linear(b)
+}
+
+// Contrast is the WCAG 2 contrast ratio of a and b, from 1 to 21.
+func Contrast(a, b Color) float64 {
+	la, lb := a.Luminance(), b.Luminance()
+	if la < lb { la, lb = lb, la }
+	return (la + 0.05) / (lb + 0.05)
+}
+
+func linear(c uint8) float64 {
+	v := float64(c) / 255
+	if v <= 0.04045 { return v / 12.92 }
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func unlinear(v float64) uint8 {
+	if v <= 0.0031308 { v *= 12.92 } else { v = 1.055*math.Pow(v, 1/2.4) - 0.055 }
+	return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
+
+// palette256 holds xterm's default RGB for every 256-color index.
+var palette256 = func() (p [256]uint32) {
+	copy(p[:16], []uint32{
+		0x000000, 0xcd0000, 0x00cd00, 0xcdcd00, 0x0000ee, 0xcd00cd, 0x00cdcd, 0xe5e5e5,
+		0x7f7f7f, 0xff0000, 0x00ff00, 0xffff00, 0x5c5cff, 0xff00ff, 0x00ffff, 0xffffff,
+	})
+	steps := [6]uint32{0, 95, 135, 175, 215, 255}
+	for i := 0; i < 216; i++ { p[16+i] = steps[i/36]<<16 | steps[i/6%6]<<8 | steps[i%6] }
+	for i := 0; i < 24; i++ {
+		v := uint32(8 + 10*i)
+		p[232+i] = v<<16 | v<<8 | v
+	}
+	return
+}()
+
+var paletteLab = func() (t [256][3]float64) {
+	for i, v := range palette256 {
+		t[i][0], t[i][1], t[i][2] = OKLab(uint8(v>>16), uint8(v>>8), uint8(v))
+	}
+	return
+}()
@@ -0,0 +1,27 @@
+package renderer
+
+import "testing"
+
+func TestHex(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Color
+		ok   bool
+	}{
+		{"#F4ACB7", RGB(0xF4, 0xAC, 0xB7), true},
+		{"#f4acb7", RGB(0xF4, 0xAC, 0xB7), true},
+		{"#fed", RGB(0xFF, 0xEE, 0xDD), true},
+		{"fed", DefaultColor, false},
+		{"bad", DefaultColor, false},
+		{"decade", DefaultColor, false},
+		{"F4ACB7", DefaultColor, false},
+		{"#F4ACB", DefaultColor, false},
+		{"#GGGGGG", DefaultColor, false},
+		{"##fed", DefaultColor, false},
+		{"", DefaultColor, false},
+	}
+	for _, tt := range tests {
+		c, err := Hex(tt.in)
+		if c != tt.want || (err == nil) != tt.ok { t.Errorf("Hex(%q) = %v, %v; want %v, ok %v", tt.in, c, err, tt.want, tt.ok) }
+	}
+}
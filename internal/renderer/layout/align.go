@@ -0,0 +1,56 @@
+package layout
+
+import "github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+
+// Justify distributes a flex line's free main-axis space ("justify").
+type Justify int
+
+const (
+	JustifyStart   Justify = iota // pack items at the start (default)
+	JustifyEnd                    // pack items at the end
+	JustifyCenter                 // center the items
+	JustifyBetween                // first and last item flush, equal space between
+	JustifyAround                 // equal space around each item (half at the edges)
+	JustifyEvenly                 // equal space between items and at the edges
+)
+
+// Align places items on their flex line's cross axis ("align").
+type Align int
+
+const (
+	AlignStretch Align = iota // fill the line (default)
+	AlignStart
+	AlignEnd
+	AlignCenter
+)
+
+// Container alignment props, and the outer spacing of a child ("margin",
+// in cells, T/R/B/L). Margins apply to in-flow flex and grid items.
+var (
+	PropJustify = renderer.NewPropKey[Justify]("justify")
+	PropAlign   = renderer.NewPropKey[Align]("align")
+	PropMargin  = renderer.NewPropKey[renderer.Padding]("margin")
+)
+
+func marginOf(n Node) renderer.Padding { return PropMargin.Or(n, renderer.Padding{}) }
+
+// justify splits free cells over the n+1 slots around n items: slot i is
+// the space before item i, slot n the space after the last one.
+func justify(j Justify, free, n int) []int {
+	w := make([]int, n+1)
+	switch j {
+	case JustifyStart: w[n] = 1
+	case JustifyEnd: w[0] = 1
+	case JustifyCenter: w[0], w[n] = 1, 1
+	case JustifyBetween:
+		for i := 1; i < n; i++ { w[i] = 1 }
+		if n == 1 { w[n] = 1 }
+	case JustifyAround:
+		for i := range w { w[i] = 2 }
+		w[0], w[n] = 1, 1
+	case JustifyEvenly:
+		for i := range w { w[i] = 1 }
+	}
+	if free <= 0 { return make([]int, n+1) }
+	return distribute(free, w)
+}
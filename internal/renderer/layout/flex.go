@@ -1 +1,408 @@
+// Package layout resolves every node of a tree to a cell-space Rect.
 package layout
+
+import (
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+)
+
+type (
+	Node = renderer.Node
+	Key  = renderer.Key
+	Rect = renderer.Rect
+)
+
+// Direction is the main axis of a flex container.
+type Direction int
+
+const (
+	Row Direction = iota
+	Column
+)
+
+// PropDirection is the container's main axis.
+var PropDirection = renderer.NewPropKey[Direction]("direction")
+
+// FlexStyle is read from container props: "direction", "gap", "wrap",
+// "justify", "align".
+type FlexStyle struct {
+	Direction Direction
+	Gap       int
+	Wrap      bool
+	Justify   Justify
+	Align     Align
+}
+
+// Props read on children: grow (int), shrink (int, default 1), basis (int),
+// w/h (int, preferred size; 0 = auto) or w-pct/h-pct (percent of the
+// container's content box), aspect (float64, width÷height) and
+// min-w/max-w/min-h/max-h (int, applied after flexing) and margin (cells
+// kept clear around the item, outside its rect).
+//
+// A "basis" prop, when present, is taken literally (WithFlex(1, 1, 0) splits
+// space evenly regardless of content); without it the basis is the explicit
+// main size, falling back to the measured content size.
+
+// StyleOf reads the container props of n.
+func StyleOf(n Node) FlexStyle {
+	p := n.Props()
+	var s FlexStyle
+	s.Direction, _ = PropDirection.Lookup(p)
+	s.Gap, _ = renderer.PropGap.Lookup(p)
+	s.Wrap, _ = renderer.PropWrap.Lookup(p)
+	s.Justify, _ = PropJustify.Lookup(p)
+	s.Align, _ = PropAlign.Lookup(p)
+	return s
+}
+
+// Result maps node keys (see renderer.Key) to their border-box rects.
+type Result map[Key]Rect
+
+// MeasureFunc reports the natural size of a leaf node when it may use at most
+// maxW columns (maxW <= 0 means unbounded).
+type MeasureFunc func(n Node, maxW int) (w, h int)
+
+// Engine lays out node trees. The zero value is ready to use.
+type Engine struct {
+	// Measure sizes leaf content; nil uses DefaultMeasurer.
+	Measure MeasureFunc
+}
+
+// Compute lays out root inside bounds with a zero Engine.
+func Compute(root Node, bounds Rect) Result { return (&Engine{}).Layout(root, bounds) }
+
+// Layout resolves root (placed exactly at bounds) and all its descendants.
+func (e *Engine) Layout(root Node, bounds Rect) Result {
+	res := Result{}
+	if root != nil { e.place(res, renderer.RootKey(root), root, bounds, bounds) }
+	return res
+}
+
+func (e *Engine) place(res Result, k Key, n Node, r, root Rect) {
+	res[k] = r
+	kids := n.Children()
+	if len(kids) == 0 { return }
+	c := Content(n, r)
+	flow := flowKids(kids)
+	var rects []Rect
+	if IsGrid(n) { rects = e.grid(n, flow, c) } else { rects = e.flex(StyleOf(n), flow, c) }
+	j := 0
+	for i, ck := range renderer.ChildKeys(k, n) {
+		kid := kids[i]
+		switch {
+		case IsPortal(kid):
+			e.place(res, ck, kid, e.portal(kid, c, root), root)
+		case PositionOf(kid) == Absolute:
+			e.place(res, ck, kid, e.absolute(kid, r, c), root)
+		case PositionOf(kid) == Relative:
+			e.place(res, ck, kid, relative(kid, rects[j]), root)
+			j++
+		default:
+			e.place(res, ck, kid, rects[j], root)
+			j++
+		}
+	}
+}
+
+// flowKids drops portals and absolutely positioned nodes, which take no
+// space in their parent.
+func flowKids(kids []Node) []Node {
+	out := kids[:0:0]
+	for _, k := range kids {
+		if inFlow(k) { out = append(out, k) }
+	}
+	return out
+}
+
+// Insets returns the space a node reserves inside its rect (border + padding).
+func Insets(n Node) (t, r, b, l int) {
+	p := n.Props()
+	if pad, ok := renderer.PropPadding.Lookup(p); ok { t, r, b, l = pad.T, pad.R, pad.B, pad.L }
+	if HasBorder(n) { t, r, b, l = t+1, r+1, b+1, l+1 }
+	return
+}
+
+// HasBorder reports whether n draws a one-cell border ("border" prop).
+func HasBorder(n Node) bool { return renderer.PropBorder.Has(n) }
+
+// Content shrinks rect by the node's insets.
+func Content(n Node, rect Rect) Rect {
+	t, r, b, l := Insets(n)
+	c := Rect{X: rect.X + l, Y: rect.Y + t, W: rect.W - l - r, H: rect.H - t - b}
+	if c.W < 0 { c.W = 0 }
+	if c.H < 0 { c.H = 0 }
+	return c
+}
+
+// hints are a child's sizing props, already mapped onto main/cross axes.
+type hints struct {
+	main, cross         int // explicit size (0 = auto)
+	minMain, maxMain    int // maxMain < 0 = unbounded
+	minCross, maxCross  int
+	grow, shrink, basis int
+	hasBasis            bool
+	aspect              float64 // width÷height, 0 = none
+}
+
+// hintsOf reads a child's hints inside container content box c.
+func hintsOf(n Node, dir Direction, c Rect) hints {
+	p := n.Props()
+	w, h := preferred(p, c.W, c.H)
+	minW, maxW, minH, maxH := limits(p)
+	hs := hints{main: w, cross: h, minMain: minW, maxMain: maxW, minCross: minH, maxCross: maxH, shrink: 1, aspect: aspectOf(p)}
+	if dir == Column {
+		hs.main, hs.cross = h, w
+		hs.minMain, hs.maxMain, hs.minCross, hs.maxCross = minH, maxH, minW, maxW
+	}
+	hs.grow, _ = renderer.PropGrow.Lookup(p)
+	if s, ok := renderer.PropShrink.Lookup(p); ok { hs.shrink = s }
+	hs.basis, hs.hasBasis = renderer.PropBasis.Lookup(p)
+	return hs
+}
+
+func clamp(v, lo, hi int) int {
+	if hi >= 0 && v > hi { v = hi }
+	if v < lo { v = lo }
+	return v
+}
+
+type item struct {
+	h             hints
+	base, size    int    // flex base size and resolved main size
+	cross         int    // hypothetical cross size
+	mMain, mCross [2]int // margins before/after on each axis
+}
+
+// outer is the main-axis space the item takes, margins included.
+func (it *item) outer() int { return it.mMain[0] + it.size + it.mMain[1] }
+
+// flex positions kids inside the container content box c.
+func (e *Engine) flex(s FlexStyle, kids []Node, c Rect) []Rect {
+	availMain, availCross := c.W, c.H
+	if s.Direction == Column { availMain, availCross = c.H, c.W }
+
+	items := make([]item, len(kids))
+	for i, k := range kids {
+		it := &items[i]
+		it.h = hintsOf(k, s.Direction, c)
+		m := marginOf(k)
+		it.mMain, it.mCross = [2]int{m.L, m.R}, [2]int{m.T, m.B}
+		if s.Direction == Column { it.mMain, it.mCross = it.mCross, it.mMain }
+		switch {
+		case it.h.hasBasis:
+			it.base = it.h.basis
+		case it.h.main > 0:
+			it.base = it.h.main
+		default:
+			w, h := e.natural(k, c.W)
+			it.base = w
+			if s.Direction == Column { it.base = h }
+		}
+		it.size = clamp(it.base, it.h.minMain, it.h.maxMain)
+	}
+
+	// Break into lines on the hypothetical main sizes.
+	var lines [][]int
+	cur, used := []int{}, 0
+	for i := range items {
+		need := items[i].outer()
+		if len(cur) > 0 { need += s.Gap }
+		if s.Wrap && len(cur) > 0 && used+need > availMain {
+			lines, cur, used = append(lines, cur), nil, 0
+			need = items[i].outer()
+		}
+		cur, used = append(cur, i), used+need
+	}
+	if len(cur) > 0 { lines = append(lines, cur) }
+
+	// crossOf is an item's own cross size: explicit, from its aspect ratio,
+	// or measured.
+	crossOf := func(i int) int {
+		it := &items[i]
+		cross := it.h.cross
+		switch {
+		case cross > 0:
+		case it.h.aspect > 0 && s.Direction == Row:
+			cross = aspectH(it.size, it.h.aspect)
+		case it.h.aspect > 0:
+			cross = aspectW(it.size, it.h.aspect)
+		case s.Direction == Row:
+			_, cross = e.natural(kids[i], it.size)
+		default:
+			cross, _ = e.natural(kids[i], availCross-it.mCross[0]-it.mCross[1])
+		}
+		return clamp(cross, it.h.minCross, it.h.maxCross)
+	}
+
+	out := make([]Rect, len(kids))
+	crossPos := 0
+	for _, line := range lines {
+		avail := availMain - s.Gap*(len(line)-1)
+		for _, i := range line { avail -= items[i].mMain[0] + items[i].mMain[1] }
+		resolveFlexible(items, line, avail)
+
+		// Line cross size: a single unwrapped line fills the container,
+		// wrapped lines are as tall (or wide) as their largest item.
+		lineCross := availCross
+		if s.Wrap {
+			lineCross = 0
+			for _, i := range line {
+				it := &items[i]
+				it.cross = crossOf(i)
+				lineCross = max(lineCross, it.mCross[0]+it.cross+it.mCross[1])
+			}
+		}
+
+		free := avail
+		for _, i := range line { free -= items[i].size }
+		slots := justify(s.Justify, free, len(line))
+
+		mainPos := slots[0]
+		for j, i := range line {
+			it := &items[i]
+			room := max(lineCross-it.mCross[0]-it.mCross[1], 0)
+			cross, off := clamp(room, it.h.minCross, it.h.maxCross), 0 // stretch
+			if s.Align != AlignStretch || it.h.cross > 0 || it.h.aspect > 0 {
+				if !s.Wrap { it.cross = crossOf(i) }
+				cross = it.cross
+			}
+			switch s.Align {
+			case AlignEnd: off = max(room-cross, 0)
+			case AlignCenter: off = max(room-cross, 0) / 2
+			}
+			m, x := mainPos+it.mMain[0], crossPos+it.mCross[0]+off
+			if s.Direction == Row {
+				out[i] = Rect{X: c.X + m, Y: c.Y + x, W: it.size, H: cross}
+			} else {
+				out[i] = Rect{X: c.X + x, Y: c.Y + m, W: cross, H: it.size}
+			}
+			mainPos += it.outer() + s.Gap + slots[j+1]
+		}
+		crossPos += lineCross + s.Gap
+	}
+	return out
+}
+
+// resolveFlexible grows or shrinks the items of one line to fill avail,
+// freezing items that hit their min/max and redistributing the remainder
+// (CSS Flexbox §9.7, in whole cells).
+func resolveFlexible(items []item, line []int, avail int) {
+	sum := 0
+	for _, i := range line { sum += items[i].size }
+	growing := sum < avail
+	if sum == avail { return }
+
+	frozen := make(map[int]bool, len(line))
+	for _, i := range line {
+		it := &items[i]
+		if (growing && it.h.grow == 0) || (!growing && (it.h.shrink == 0 || it.base == 0)) {
+			frozen[i] = true
+		}
+	}
+	for len(frozen) < len(line) {
+		free := avail
+		var open []int
+		var weights []int
+		for _, i := range line {
+			if frozen[i] { free -= items[i].size; continue }
+			free -= items[i].base
+			open = append(open, i)
+			if growing { weights = append(weights, items[i].h.grow) } else { weights = append(weights, items[i].h.shrink*items[i].base) }
+		}
+		shares := distribute(free, weights)
+		want := make([]int, len(open))
+		violation := 0
+		for j, i := range open {
+			it := &items[i]
+			want[j] = it.base + shares[j]
+			it.size = clamp(want[j], it.h.minMain, it.h.maxMain)
+			violation += it.size - want[j]
+		}
+		// Freeze min violators when the total violation is positive, max
+		// violators when negative, everyone when zero.
+		for j, i := range open {
+			size := items[i].size
+			if violation == 0 || (violation > 0 && size > want[j]) || (violation < 0 && size < want[j]) {
+				frozen[i] = true
+			}
+		}
+	}
+}
+
+// distribute splits total across weights proportionally using the largest
+// remainder method so the shares always sum to exactly total.
+func distribute(total int, weights []int) []int {
+	out := make([]int, len(weights))
+	sum := 0
+	for _, w := range weights { sum += w }
+	if sum <= 0 || total == 0 { return out }
+	sign := 1
+	if total < 0 { sign, total = -1, -total }
+	rem := make([]int, len(weights))
+	given := 0
+	for i, w := range weights {
+		out[i] = total * w / sum
+		rem[i] = total * w % sum
+		given += out[i]
+	}
+	for ; given < total; given++ {
+		best := 0
+		for i := range rem { if rem[i] > rem[best] { best = i } }
+		out[best]++
+		rem[best] = -1
+	}
+	for i := range out { out[i] *= sign }
+	return out
+}
+
+// natural reports the content size of n when it may use at most maxW columns
+// (percent widths resolve against maxW).
+func (e *Engine) natural(n Node, maxW int) (w, h int) {
+	p := n.Props()
+	ew, eh := preferred(p, maxW, 0)
+	if ew > 0 { maxW = ew }
+	kids := flowKids(n.Children())
+	switch {
+	case ew > 0 && eh > 0:
+		w, h = ew, eh
+	case len(kids) == 0:
+		measure := e.Measure
+		if measure == nil { measure = DefaultMeasurer.Measure }
+		t, r, b, l := Insets(n)
+		inner := 0 // unbounded
+		if maxW > 0 { inner = max(maxW-l-r, 1) }
+		w, h = measure(n, inner)
+		w, h = w+l+r, h+t+b
+	default:
+		t, r, b, l := Insets(n)
+		inner := 0 // unbounded
+		if maxW > 0 { inner = max(maxW-l-r, 1) }
+		if IsGrid(n) {
+			w, h = e.gridNatural(n, kids, inner)
+			w, h = w+l+r, h+t+b
+			break
+		}
+		s := StyleOf(n)
+		lineMain, lineCross, totalMain, totalCross, count := 0, 0, 0, 0, 0
+		for _, k := range kids {
+			m := marginOf(k)
+			kw, kh := e.natural(k, inner)
+			km, kc := kw+m.L+m.R, kh+m.T+m.B
+			if s.Direction == Column { km, kc = kc, km }
+			if s.Wrap && s.Direction == Row && inner > 0 && count > 0 && lineMain+s.Gap+km > inner {
+				totalMain, totalCross = max(totalMain, lineMain), totalCross+lineCross+s.Gap
+				lineMain, lineCross, count = 0, 0, 0
+			}
+			if count > 0 { lineMain += s.Gap }
+			lineMain, lineCross, count = lineMain+km, max(lineCross, kc), count+1
+		}
+		totalMain, totalCross = max(totalMain, lineMain), totalCross+lineCross
+		w, h = totalMain, totalCross
+		if s.Direction == Column { w, h = totalCross, totalMain }
+		w, h = w+l+r, h+t+b
+	}
+	if ew > 0 { w = ew }
+	if eh > 0 { h = eh }
+	if a := aspectOf(p); a > 0 && ew == 0 && eh == 0 { h = aspectH(w, a) }
+	minW, maxWp, minH, maxHp := limits(p)
+	return clamp(w, minW, maxWp), clamp(h, minH, maxHp)
+}
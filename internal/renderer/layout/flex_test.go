@@ -0,0 +1,120 @@
+package layout_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/layout"
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui/uitest"
+)
+
+// item is a 1-row box of base width w.
+func item(id string, w int, opts ...ui.NodeOption) ui.Node {
+	return ui.Box(id, append([]ui.NodeOption{ui.WithSize(w, 1)}, opts...)...)
+}
+
+// spans lays root out in w×h and lists its children as "id:x+w".
+func spans(root ui.Node, w, h int) string {
+	res := layout.Compute(root, ui.Rect{W: w, H: h})
+	var out []string
+	for _, k := range root.Children() {
+		r := res[ui.Key("r/"+string(k.ID()))]
+		out = append(out, fmt.Sprintf("%s:%d+%d", k.ID(), r.X, r.W))
+	}
+	return strings.Join(out, " ")
+}
+
+func TestFlexGrowShrink(t *testing.T) {
+	tests := []struct {
+		name string
+		kids []ui.Node
+		want string
+	}{
+		{"no grow", []ui.Node{item("a", 4), item("b", 6)}, "a:0+4 b:4+6"},
+		{"grow one", []ui.Node{item("a", 4), item("b", 6, ui.WithFlex(1, 1, 6))}, "a:0+4 b:4+16"},
+		{"grow 1:3", []ui.Node{item("a", 0, ui.WithFlex(1, 1, 0)), item("b", 0, ui.WithFlex(3, 1, 0))}, "a:0+5 b:5+15"},
+		{"grow shares remainder", []ui.Node{item("a", 0, ui.WithFlex(1, 1, 0)), item("b", 0, ui.WithFlex(1, 1, 0)), item("c", 0, ui.WithFlex(1, 1, 0))}, "a:0+7 b:7+7 c:14+6"},
+		{"grow capped by max", []ui.Node{item("a", 0, ui.WithFlex(1, 1, 0), ui.WithMaxWidth(3)), item("b", 0, ui.WithFlex(1, 1, 0))}, "a:0+3 b:3+17"},
+		{"shrink by basis", []ui.Node{item("a", 10, ui.WithFlex(0, 1, 10)), item("b", 30, ui.WithFlex(0, 1, 30))}, "a:0+5 b:5+15"},
+		{"no shrink", []ui.Node{item("a", 12, ui.WithFlex(0, 0, 12)), item("b", 12, ui.WithFlex(0, 1, 12))}, "a:0+12 b:12+8"},
+		{"shrink floored by min", []ui.Node{item("a", 10, ui.WithFlex(0, 1, 10), ui.WithMinWidth(9)), item("b", 30, ui.WithFlex(0, 1, 30))}, "a:0+9 b:9+11"},
+		{"min beats basis", []ui.Node{item("a", 2, ui.WithMinWidth(5)), item("b", 2)}, "a:0+5 b:5+2"},
+		{"max beats basis", []ui.Node{item("a", 12, ui.WithMaxWidth(8)), item("b", 2)}, "a:0+8 b:8+2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := ui.Box("r", ui.WithChildren(tt.kids...))
+			if got := spans(root, 20, 1); got != tt.want { t.Errorf("got %s, want %s", got, tt.want) }
+		})
+	}
+}
+
+func TestFlexJustify(t *testing.T) {
+	tests := []struct {
+		j    ui.Justify
+		gap  int
+		want string
+	}{
+		{ui.JustifyStart, 0, "a:0+2 b:2+2 c:4+2"},
+		{ui.JustifyEnd, 0, "a:14+2 b:16+2 c:18+2"},
+		{ui.JustifyCenter, 0, "a:7+2 b:9+2 c:11+2"},
+		{ui.JustifyBetween, 0, "a:0+2 b:9+2 c:18+2"},
+		{ui.JustifyAround, 0, "a:2+2 b:9+2 c:16+2"},
+		{ui.JustifyEvenly, 0, "a:4+2 b:10+2 c:15+2"}, // 14 free cells over 4 slots: 4,4,3,3
+		{ui.JustifyBetween, 2, "a:0+2 b:9+2 c:18+2"},
+		{ui.JustifyCenter, 2, "a:5+2 b:9+2 c:13+2"},
+	}
+	for _, tt := range tests {
+		root := ui.Box("r", ui.WithJustify(tt.j), ui.WithGap(tt.gap), ui.WithChildren(item("a", 2), item("b", 2), item("c", 2)))
+		if got := spans(root, 20, 1); got != tt.want { t.Errorf("justify %d gap %d: got %s, want %s", tt.j, tt.gap, got, tt.want) }
+	}
+}
+
+func TestFlexColumnAlign(t *testing.T) {
+	root := ui.Box("r", ui.WithDirection(ui.Column), ui.WithAlign(ui.AlignCenter), ui.WithJustify(ui.JustifyEnd), ui.WithChildren(
+		ui.Text("a", "hi", ui.Attr{}),
+		ui.Text("b", "there", ui.Attr{}),
+	))
+	s := uitest.Render(root, 9, 4)
+	uitest.Golden(t, s, "flex-column-center")
+	uitest.AssertText(t, s, 3, 2, "hi")
+	uitest.AssertText(t, s, 2, 3, "there")
+}
+
+func TestFlexMargins(t *testing.T) {
+	m := func(t, r, b, l int) ui.NodeOption { return ui.WithMargin(ui.Padding{T: t, R: r, B: b, L: l}) }
+	box := func(id string, w, h int, opts ...ui.NodeOption) ui.Node {
+		return ui.Box(id, append([]ui.NodeOption{ui.WithSize(w, h)}, opts...)...)
+	}
+	tests := []struct {
+		name  string
+		opts  []ui.NodeOption // container
+		a     ui.Node
+		wantA string
+		wantB string // b is a 3×1 box after a, basis 3, no shrink
+	}{
+		{"row", nil, box("a", 4, 1, m(0, 1, 0, 2)), "2,0 4x1", "7,0 3x1"},
+		{"row with gap", []ui.NodeOption{ui.WithGap(2)}, box("a", 4, 1, m(0, 1, 0, 2)), "2,0 4x1", "9,0 3x1"},
+		{"column", []ui.NodeOption{ui.WithDirection(ui.Column)}, box("a", 4, 1, m(1, 0, 2, 0)), "0,1 4x1", "0,4 3x3"}, // b's basis is its height here
+		{"cross margin", []ui.NodeOption{ui.WithAlign(ui.AlignStart)}, box("a", 4, 1, m(2, 0, 0, 1)), "1,2 4x1", "5,0 3x1"},
+		{"stretch inside margins", nil, ui.Box("a", ui.WithSize(4, 0), m(1, 0, 2, 0)), "0,1 4x5", "4,0 3x1"},
+		{"grow inside margins", nil, box("a", 0, 1, ui.WithFlex(1, 1, 0), m(0, 2, 0, 2)), "2,0 13x1", "17,0 3x1"},
+		{"justify end", []ui.NodeOption{ui.WithJustify(ui.JustifyEnd)}, box("a", 4, 1, m(0, 3, 0, 1)), "10,0 4x1", "17,0 3x1"},
+		{"shrink keeps margins", nil, box("a", 30, 1, ui.WithFlex(0, 1, 30), m(0, 1, 0, 1)), "1,0 15x1", "17,0 3x1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := ui.Box("r", append(tt.opts, ui.WithChildren(tt.a, box("b", 3, 1, ui.WithFlex(0, 0, 3))))...)
+			if got := rectOf(root, 20, 8, "a"); got != tt.wantA { t.Errorf("a = %s, want %s", got, tt.wantA) }
+			if got := rectOf(root, 20, 8, "b"); got != tt.wantB { t.Errorf("b = %s, want %s", got, tt.wantB) }
+		})
+	}
+}
+
+func TestFlexMarginsInNaturalSize(t *testing.T) {
+	inner := ui.Box("p", ui.WithChildren(item("a", 4, ui.WithMargin(ui.Padding{T: 1, R: 2, B: 1, L: 1}))))
+	root := ui.Box("r", ui.WithAlign(ui.AlignStart), ui.WithChildren(inner))
+	if got := rectOf(root, 20, 6, "p"); got != "0,0 7x3" { t.Errorf("p = %s, want 0,0 7x3 (a plus its margins)", got) }
+}
@@ -0,0 +1,271 @@
+package layout
+
+import (
+	"strings"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+)
+
+// Display selects a container's layout mode ("display").
+type Display int
+
+const (
+	DisplayFlex Display = iota // default
+	DisplayGrid
+)
+
+// TrackKind is how a grid track is sized.
+type TrackKind int
+
+const (
+	TrackAuto     TrackKind = iota // as large as its largest single-span item
+	TrackFixed                     // N cells
+	TrackFr                        // N shares of the space left after fixed and auto tracks
+	TrackAutoFill                  // as many 1fr columns of at least N cells as fit
+)
+
+// Track is one grid row or column.
+type Track struct {
+	Kind TrackKind
+	N    int
+}
+
+// Grid props. Containers: "display", "grid-cols", "grid-rows" (missing or
+// implicit rows are auto), "grid-areas" (one string per row of
+// space-separated names, "." for an empty cell), "row-gap" and "col-gap"
+// (both default to "gap"). Items: "area", or "row"/"col" (1-based; 0 = auto
+// placement) with "row-span"/"col-span" (default 1).
+var (
+	PropDisplay   = renderer.NewPropKey[Display]("display")
+	PropGridCols  = renderer.NewPropKey[[]Track]("grid-cols")
+	PropGridRows  = renderer.NewPropKey[[]Track]("grid-rows")
+	PropGridAreas = renderer.NewPropKey[[]string]("grid-areas")
+	PropRowGap    = renderer.NewPropKey[int]("row-gap")
+	PropColGap    = renderer.NewPropKey[int]("col-gap")
+	PropArea      = renderer.NewPropKey[string]("area")
+	PropRow       = renderer.NewPropKey[int]("row")
+	PropCol       = renderer.NewPropKey[int]("col")
+	PropRowSpan   = renderer.NewPropKey[int]("row-span")
+	PropColSpan   = renderer.NewPropKey[int]("col-span")
+)
+
+// IsGrid reports whether n lays its children out as a grid.
+func IsGrid(n Node) bool { return PropDisplay.Or(n, DisplayFlex) == DisplayGrid }
+
+// cell is a grid item's resolved placement (0-based tracks).
+type cell struct{ row, col, rows, cols int }
+
+type gridSpec struct {
+	cols, rows     []Track
+	rowGap, colGap int
+	areas          map[string]cell
+	places         []cell
+}
+
+// newGridSpec reads n's grid props and places kids; width is the content
+// width auto-fill columns are counted against (0 = unknown).
+func newGridSpec(n Node, kids []Node, width int) gridSpec {
+	p := n.Props()
+	g := gridSpec{areas: map[string]cell{}}
+	gap, _ := renderer.PropGap.Lookup(p)
+	g.rowGap, g.colGap = gap, gap
+	if v, ok := PropRowGap.Lookup(p); ok { g.rowGap = v }
+	if v, ok := PropColGap.Lookup(p); ok { g.colGap = v }
+	g.cols, _ = PropGridCols.Lookup(p)
+	g.rows, _ = PropGridRows.Lookup(p)
+
+	areaCols := 0
+	if rows, ok := PropGridAreas.Lookup(p); ok {
+		for r, line := range rows {
+			names := strings.Fields(line)
+			areaCols = max(areaCols, len(names))
+			for c, name := range names {
+				if name == "." { continue }
+				a, seen := g.areas[name]
+				if !seen { a = cell{r, c, 1, 1} }
+				// Areas are rectangles: grow the bounding box.
+				if end := max(a.row+a.rows, r+1); end > a.row+a.rows { a.rows = end - a.row }
+				if end := max(a.col+a.cols, c+1); end > a.col+a.cols { a.cols = end - a.col }
+				g.areas[name] = a
+			}
+		}
+		for len(g.rows) < len(rows) { g.rows = append(g.rows, Track{Kind: TrackAuto}) }
+	}
+	// repeat(auto-fill, minmax(N, 1fr))
+	if len(g.cols) == 1 && g.cols[0].Kind == TrackAutoFill {
+		minW := max(g.cols[0].N, 1)
+		count := 1
+		if width > 0 { count = max((width+g.colGap)/(minW+g.colGap), 1) }
+		g.cols = make([]Track, count)
+		for i := range g.cols { g.cols[i] = Track{Kind: TrackFr, N: 1} }
+	}
+	for len(g.cols) < max(areaCols, 1) { g.cols = append(g.cols, Track{Kind: TrackAuto}) }
+
+	g.places = g.place(kids)
+	for _, c := range g.places {
+		for len(g.rows) < c.row+c.rows { g.rows = append(g.rows, Track{Kind: TrackAuto}) }
+	}
+	return g
+}
+
+// place resolves every item's cell: named areas and explicit rows/columns
+// first, then the rest row by row into the first free slot.
+func (g *gridSpec) place(kids []Node) []cell {
+	ncols := len(g.cols)
+	used := map[[2]int]bool{}
+	mark := func(c cell) {
+		for r := c.row; r < c.row+c.rows; r++ {
+			for col := c.col; col < c.col+c.cols; col++ { used[[2]int{r, col}] = true }
+		}
+	}
+	free := func(c cell) bool {
+		if c.col+c.cols > ncols { return false }
+		for r := c.row; r < c.row+c.rows; r++ {
+			for col := c.col; col < c.col+c.cols; col++ {
+				if used[[2]int{r, col}] { return false }
+			}
+		}
+		return true
+	}
+
+	out := make([]cell, len(kids))
+	auto := make([]bool, len(kids))
+	for i, k := range kids {
+		p := k.Props()
+		if name, ok := PropArea.Lookup(p); ok {
+			if a, ok := g.areas[name]; ok { out[i] = a; mark(a); continue }
+		}
+		rows := max(PropRowSpan.Or(k, 1), 1)
+		cols := min(max(PropColSpan.Or(k, 1), 1), ncols)
+		row, _ := PropRow.Lookup(p)
+		col, _ := PropCol.Lookup(p)
+		out[i] = cell{row - 1, col - 1, rows, cols}
+		switch {
+		case row > 0 && col > 0:
+			out[i].col = min(out[i].col, ncols-cols)
+			mark(out[i])
+		case row > 0 || col > 0:
+			// One axis pinned: slide along the other to the first free slot.
+			// A full pinned row leaves the item to auto placement.
+			c := &out[i]
+			if row > 0 {
+				for c.col = 0; c.col+cols < ncols && !free(*c); c.col++ {}
+				if !free(*c) { auto[i] = true; continue }
+			} else {
+				c.col = min(c.col, ncols-cols)
+				for c.row = 0; !free(*c); c.row++ {}
+			}
+			mark(*c)
+		default:
+			auto[i] = true
+		}
+	}
+	r, c := 0, 0
+	for i := range kids {
+		if !auto[i] { continue }
+		it := &out[i]
+		for {
+			if c+it.cols > ncols { r, c = r+1, 0 }
+			it.row, it.col = r, c
+			if free(*it) { break }
+			c++
+		}
+		mark(*it)
+		c += it.cols
+	}
+	return out
+}
+
+// sizes resolves tracks to cells. Auto tracks take the largest measure(i) of
+// the single-span items starting in them (along reports an item's first
+// track and span on this axis); fr tracks share what is left of avail. With
+// avail < 0 (unbounded) fr tracks behave like auto ones. Spanning items do
+// not grow auto tracks.
+func sizes(tracks []Track, gap, avail int, places []cell, along func(cell) (int, int), measure func(i int) int) []int {
+	out := make([]int, len(tracks))
+	for t, tr := range tracks {
+		switch {
+		case tr.Kind == TrackFixed:
+			out[t] = tr.N
+		case tr.Kind == TrackAuto || avail < 0:
+			for i, pl := range places {
+				if start, span := along(pl); start == t && span == 1 { out[t] = max(out[t], measure(i)) }
+			}
+		}
+	}
+	if avail < 0 { return out }
+	left := avail - gap*(len(tracks)-1)
+	var weights []int
+	var frs []int
+	for t, tr := range tracks {
+		if tr.Kind == TrackFr { weights, frs = append(weights, tr.N), append(frs, t) } else { left -= out[t] }
+	}
+	for j, s := range distribute(max(left, 0), weights) { out[frs[j]] = s }
+	return out
+}
+
+// span returns the offset of track start and the extent of count tracks.
+func span(ts []int, gap, start, count int) (off, ext int) {
+	for i := 0; i < start; i++ { off += ts[i] + gap }
+	for i := start; i < start+count && i < len(ts); i++ { ext += ts[i] }
+	return off, ext + gap*(count-1)
+}
+
+// grid positions kids inside the container content box c.
+func (e *Engine) grid(n Node, kids []Node, c Rect) []Rect {
+	g := newGridSpec(n, kids, c.W)
+	colSizes := sizes(g.cols, g.colGap, c.W, g.places,
+		func(pl cell) (int, int) { return pl.col, pl.cols },
+		func(i int) int { w, _ := e.outer(kids[i], c.W); return w })
+	rowSizes := sizes(g.rows, g.rowGap, c.H, g.places,
+		func(pl cell) (int, int) { return pl.row, pl.rows },
+		func(i int) int {
+			_, w := span(colSizes, g.colGap, g.places[i].col, g.places[i].cols)
+			_, h := e.outer(kids[i], w)
+			return h
+		})
+	out := make([]Rect, len(kids))
+	for i, pl := range g.places {
+		x, w := span(colSizes, g.colGap, pl.col, pl.cols)
+		y, h := span(rowSizes, g.rowGap, pl.row, pl.rows)
+		m := marginOf(kids[i])
+		x, y, w, h = x+m.L, y+m.T, max(w-m.L-m.R, 0), max(h-m.T-m.B, 0)
+		// Items stretch over their area; an explicit size pins them to its start.
+		ew, eh := preferred(kids[i].Props(), w, h)
+		if ew > 0 { w = min(w, ew) }
+		if eh > 0 { h = min(h, eh) }
+		minW, maxW, minH, maxH := limits(kids[i].Props())
+		w, h = clamp(w, minW, maxW), clamp(h, minH, maxH)
+		out[i] = Rect{X: c.X + x, Y: c.Y + y, W: w, H: h}
+	}
+	return out
+}
+
+// gridNatural is the content size of a grid whose content may use at most
+// maxW columns (0 = unbounded).
+func (e *Engine) gridNatural(n Node, kids []Node, maxW int) (w, h int) {
+	g := newGridSpec(n, kids, maxW)
+	availW := -1
+	if maxW > 0 { availW = maxW }
+	colSizes := sizes(g.cols, g.colGap, availW, g.places,
+		func(pl cell) (int, int) { return pl.col, pl.cols },
+		func(i int) int { w, _ := e.outer(kids[i], maxW); return w })
+	rowSizes := sizes(g.rows, g.rowGap, -1, g.places,
+		func(pl cell) (int, int) { return pl.row, pl.rows },
+		func(i int) int {
+			_, w := span(colSizes, g.colGap, g.places[i].col, g.places[i].cols)
+			_, h := e.outer(kids[i], w)
+			return h
+		})
+	_, w = span(colSizes, g.colGap, 0, len(colSizes))
+	_, h = span(rowSizes, g.rowGap, 0, len(rowSizes))
+	return max(w, 0), max(h, 0)
+}
+
+// outer is n's natural size plus its margins, measured in an area maxW wide.
+func (e *Engine) outer(n Node, maxW int) (w, h int) {
+	m := marginOf(n)
+	if maxW > 0 { maxW = max(maxW-m.L-m.R, 1) }
+	w, h = e.natural(n, maxW)
+	return w + m.L + m.R, h + m.T + m.B
+}
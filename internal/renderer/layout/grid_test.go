@@ -0,0 +1,111 @@
+package layout_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/layout"
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+)
+
+// cells lays root out in w×h and lists its children as "id:x,y wxh".
+func cells(root ui.Node, w, h int) string {
+	res := layout.Compute(root, ui.Rect{W: w, H: h})
+	var out []string
+	for _, k := range root.Children() {
+		r := res[ui.Key("r/"+string(k.ID()))]
+		out = append(out, fmt.Sprintf("%s:%d,%d %dx%d", k.ID(), r.X, r.Y, r.W, r.H))
+	}
+	return strings.Join(out, " ")
+}
+
+func boxes(ids ...string) []ui.Node {
+	out := make([]ui.Node, len(ids))
+	for i, id := range ids { out[i] = ui.Box(id) }
+	return out
+}
+
+func TestGridTracks(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ui.NodeOption
+		kids []ui.Node
+		want string
+	}{
+		{"fixed and fr", []ui.NodeOption{ui.WithGridColumns(ui.Cells(4), ui.Fr(1), ui.Fr(3)), ui.WithGridRows(ui.Cells(2))},
+			boxes("a", "b", "c"), "a:0,0 4x2 b:4,0 4x2 c:8,0 12x2"},
+		{"auto fits content", []ui.NodeOption{ui.WithGridColumns(ui.Auto, ui.Fr(1)), ui.WithGridRows(ui.Cells(1))},
+			[]ui.Node{ui.Text("a", "label", ui.Attr{}), ui.Box("b")}, "a:0,0 5x1 b:5,0 15x1"},
+		{"gaps", []ui.NodeOption{ui.WithGridColumns(ui.Fr(1), ui.Fr(1)), ui.WithGridRows(ui.Cells(1), ui.Cells(1)), ui.WithGridGap(1, 2)},
+			boxes("a", "b", "c", "d"), "a:0,0 9x1 b:11,0 9x1 c:0,2 9x1 d:11,2 9x1"},
+		{"fr rows share height", []ui.NodeOption{ui.WithGridColumns(ui.Fr(1)), ui.WithGridRows(ui.Fr(1), ui.Fr(2))},
+			boxes("a", "b"), "a:0,0 20x2 b:0,2 20x4"},
+		{"fixed overflow starves fr", []ui.NodeOption{ui.WithGridColumns(ui.Cells(15), ui.Cells(10), ui.Fr(1)), ui.WithGridRows(ui.Cells(1))},
+			boxes("a", "b", "c"), "a:0,0 15x1 b:15,0 10x1 c:25,0 0x1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := ui.Box("r", append(tt.opts, ui.WithChildren(tt.kids...))...)
+			if got := cells(root, 20, 6); got != tt.want { t.Errorf("got  %s\nwant %s", got, tt.want) }
+		})
+	}
+}
+
+func TestGridPlacement(t *testing.T) {
+	cols := ui.WithGridColumns(ui.Fr(1), ui.Fr(1), ui.Fr(1))
+	rows := ui.WithGridRows(ui.Cells(1), ui.Cells(1), ui.Cells(1))
+	tests := []struct {
+		name string
+		opts []ui.NodeOption
+		kids []ui.Node
+		want string
+	}{
+		{"auto flow wraps", nil, boxes("a", "b", "c", "d"), "a:0,0 4x1 b:4,0 4x1 c:8,0 4x1 d:0,1 4x1"},
+		{"col span", nil, []ui.Node{ui.Box("a", ui.WithSpan(1, 2)), ui.Box("b"), ui.Box("c")},
+			"a:0,0 8x1 b:8,0 4x1 c:0,1 4x1"},
+		{"span skips to the next row", nil, []ui.Node{ui.Box("a"), ui.Box("b"), ui.Box("c", ui.WithSpan(1, 2))},
+			"a:0,0 4x1 b:4,0 4x1 c:0,1 8x1"},
+		{"row span blocks auto flow", nil, []ui.Node{ui.Box("a", ui.WithSpan(2, 1)), ui.Box("b"), ui.Box("c"), ui.Box("d"), ui.Box("e")},
+			"a:0,0 4x2 b:4,0 4x1 c:8,0 4x1 d:4,1 4x1 e:8,1 4x1"},
+		{"pinned cell", nil, []ui.Node{ui.Box("a"), ui.Box("b", ui.WithGridCell(1, 1)), ui.Box("c")},
+			"a:4,0 4x1 b:0,0 4x1 c:8,0 4x1"},
+		{"pinned column", nil, []ui.Node{ui.Box("a", ui.WithGridCell(0, 3)), ui.Box("b", ui.WithGridCell(0, 3))},
+			"a:8,0 4x1 b:8,1 4x1"},
+		{"pinned row full", nil, []ui.Node{ui.Box("a", ui.WithGridCell(1, 0)), ui.Box("b", ui.WithGridCell(1, 0)), ui.Box("c", ui.WithGridCell(1, 0)), ui.Box("d", ui.WithGridCell(1, 0))},
+			"a:0,0 4x1 b:4,0 4x1 c:8,0 4x1 d:0,1 4x1"},
+		{"span clamped to columns", nil, []ui.Node{ui.Box("a", ui.WithSpan(1, 5))}, "a:0,0 12x1"},
+		{"areas", []ui.NodeOption{ui.WithGridAreas("head head head", "side main main", "side foot .")},
+			[]ui.Node{ui.Box("f", ui.WithArea("foot")), ui.Box("h", ui.WithArea("head")), ui.Box("s", ui.WithArea("side")), ui.Box("m", ui.WithArea("main"))},
+			"f:4,2 4x1 h:0,0 12x1 s:0,1 4x2 m:4,1 8x1"},
+		{"unknown area flows", []ui.NodeOption{ui.WithGridAreas("a b c")}, []ui.Node{ui.Box("x", ui.WithArea("b")), ui.Box("y", ui.WithArea("nope"))},
+			"x:4,0 4x1 y:0,0 4x1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]ui.NodeOption{cols, rows}, tt.opts...)
+			root := ui.Box("r", append(opts, ui.WithChildren(tt.kids...))...)
+			if got := cells(root, 12, 3); got != tt.want { t.Errorf("got  %s\nwant %s", got, tt.want) }
+		})
+	}
+}
+
+func TestGridAutoFillReflow(t *testing.T) {
+	tiles := boxes("a", "b", "c", "d")
+	tests := []struct {
+		w    int
+		want string
+	}{
+		{30, "a:0,0 7x1 b:8,0 7x1 c:16,0 7x1 d:24,0 6x1"},
+		{20, "a:0,0 6x1 b:7,0 6x1 c:14,0 6x1 d:0,1 6x1"},
+		{13, "a:0,0 6x1 b:7,0 6x1 c:0,1 6x1 d:7,1 6x1"},
+		{5, "a:0,0 5x1 b:0,1 5x1 c:0,2 5x1 d:0,3 5x1"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.w), func(t *testing.T) {
+			root := ui.Box("r", ui.WithGridColumns(ui.AutoFill(6)), ui.WithGridRows(ui.Cells(1), ui.Cells(1), ui.Cells(1), ui.Cells(1)),
+				ui.WithGridGap(0, 1), ui.WithChildren(tiles...))
+			if got := cells(root, tt.w, 4); got != tt.want { t.Errorf("got  %s\nwant %s", got, tt.want) }
+		})
+	}
+}
@@ -1 +1,192 @@
-package layout
\ No newline at end of file
+package layout
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+	"github.com/rivo/uniseg"
+)
+
+// TabWidth is the distance between tab stops when expanding '\t'.
+const TabWidth = 8
+
+// Cluster is one user-perceived character and the cells it occupies
+// (2 for CJK and most emoji, 1 for ASCII, 0 for stray zero-width marks).
+type Cluster struct {
+	Text  string
+	Width int
+}
+
+// Clusters splits a single line into grapheme clusters. Combining marks and
+// ZWJ emoji sequences stay attached to their base character.
+func Clusters(line string) []Cluster {
+	var out []Cluster
+	state := -1
+	for line != "" {
+		var c string
+		var w int
+		c, line, w, state = uniseg.FirstGraphemeClusterInString(line, state)
+		out = append(out, Cluster{c, w})
+	}
+	return out
+}
+
+// StringWidth returns the display width of the widest line of s.
+func StringWidth(s string) int {
+	w := 0
+	for _, line := range strings.Split(expandTabs(s), "\n") { w = max(w, uniseg.StringWidth(line)) }
+	return w
+}
+
+// expandTabs replaces tabs with spaces up to the next TabWidth stop,
+// counting columns per line by cluster width.
+func expandTabs(s string) string {
+	if !strings.Contains(s, "\t") { return s }
+	var b strings.Builder
+	col := 0
+	state := -1
+	for s != "" {
+		var c string
+		var w int
+		c, s, w, state = uniseg.FirstGraphemeClusterInString(s, state)
+		switch c {
+		case "\t":
+			n := TabWidth - col%TabWidth
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+		case "\n", "\r\n":
+			b.WriteString(c)
+			col = 0
+		default:
+			b.WriteString(c)
+			col += w
+		}
+	}
+	return b.String()
+}
+
+// Measurer wraps and measures text, memoizing results by (content, width).
+// It is safe for concurrent use.
+type Measurer struct {
+	mu    sync.Mutex
+	limit int
+	cache map[measureKey][]string
+}
+
+type measureKey struct {
+	s string
+	w int
+}
+
+// NewMeasurer returns a Measurer caching up to limit entries; once full the
+// cache is dropped wholesale, which keeps steady-state frames allocation-free.
+func NewMeasurer(limit int) *Measurer {
+	if limit <= 0 { limit = 4096 }
+	return &Measurer{limit: limit, cache: make(map[measureKey][]string)}
+}
+
+// DefaultMeasurer backs Engine.Measure when none is configured.
+var DefaultMeasurer = NewMeasurer(4096)
+
+// Wrap breaks s into lines no wider than width (width <= 0: only at '\n').
+// Breaks follow Unicode line-breaking rules (UAX #14), so CJK text wraps
+// between ideographs and Latin text between words; words longer than width
+// are split between grapheme clusters. The result must not be modified.
+func (m *Measurer) Wrap(s string, width int) []string {
+	if width < 0 { width = 0 }
+	k := measureKey{s, width}
+	m.mu.Lock()
+	lines, ok := m.cache[k]
+	m.mu.Unlock()
+	if ok { return lines }
+
+	lines = wrap(s, width)
+	m.mu.Lock()
+	if len(m.cache) >= m.limit { m.cache = make(map[measureKey][]string) }
+	m.cache[k] = lines
+	m.mu.Unlock()
+	return lines
+}
+
+// Size returns the width of the widest wrapped line and the line count.
+func (m *Measurer) Size(s string, width int) (w, h int) {
+	lines := m.Wrap(s, width)
+	for _, l := range lines { w = max(w, uniseg.StringWidth(l)) }
+	return w, len(lines)
+}
+
+// Measure is a MeasureFunc reading the "text" prop.
+func (m *Measurer) Measure(n Node, maxW int) (w, h int) {
+	text, _ := renderer.PropText.Get(n)
+	return m.Size(text, maxW)
+}
+
+// Len reports the number of cached entries (for perf counters).
+func (m *Measurer) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.cache)
+}
+
+func wrap(s string, width int) []string {
+	if s == "" { return nil }
+	var out []string
+	for _, para := range strings.Split(strings.ReplaceAll(expandTabs(s), "\r\n", "\n"), "\n") {
+		if width <= 0 || uniseg.StringWidth(para) <= width {
+			out = append(out, para)
+			continue
+		}
+		out = append(out, wrapLine(para, width)...)
+	}
+	return out
+}
+
+// wrapLine greedily packs line-break segments; trailing spaces of a segment
+// may overhang the edge and are trimmed from the emitted line.
+func wrapLine(s string, width int) []string {
+	var out []string
+	var cur strings.Builder
+	curW := 0
+	flush := func() {
+		out = append(out, strings.TrimRight(cur.String(), " "))
+		cur.Reset()
+		curW = 0
+	}
+	state := -1
+	for s != "" {
+		var seg string
+		seg, s, _, state = uniseg.FirstLineSegmentInString(s, state)
+		body := strings.TrimRight(seg, " ")
+		bw, sw := uniseg.StringWidth(body), uniseg.StringWidth(seg)
+		if curW > 0 && curW+bw > width {
+			if strings.TrimLeft(cur.String(), " ") == "" {
+				// Indentation alone does not earn a line of its own.
+				cur.Reset()
+				curW = 0
+			} else {
+				flush()
+			}
+		}
+		if bw > width {
+			// Hard-break an over-long segment between clusters.
+			for _, c := range Clusters(body) {
+				if curW > 0 && curW+c.Width > width { flush() }
+				cur.WriteString(c.Text)
+				curW += c.Width
+			}
+			cur.WriteString(seg[len(body):])
+			curW += sw - bw
+			continue
+		}
+		if curW == 0 && len(out) > 0 {
+			// Continuation lines do not start with the space we broke at.
+			seg = strings.TrimLeft(seg, " ")
+			sw = uniseg.StringWidth(seg)
+		}
+		cur.WriteString(seg)
+		curW += sw
+	}
+	if cur.Len() > 0 || len(out) == 0 { flush() }
+	return out
+}
@@ -0,0 +1,77 @@
+package layout
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"日本語", 6},
+		{"ｈｉ", 4}, // fullwidth Latin
+		{"é", 1}, // e + combining acute
+		{"👍", 2},
+		{"👍🏽", 2},           // skin tone modifier
+		{"👨‍👩‍👧", 2},        // ZWJ family
+		{"🇯🇵", 2},           // flag
+		{"a\tb", 9},         // tab to column 8
+		{"abcdefgh\tx", 17}, // tab at a stop jumps a full stop
+		{"日本\tx", 9},        // wide clusters count two columns
+		{"ab\ncdef", 4},     // widest line
+		{"x\n\ty", 9},       // tab stops restart per line
+	}
+	for _, tt := range tests {
+		if got := StringWidth(tt.s); got != tt.want { t.Errorf("StringWidth(%q) = %d, want %d", tt.s, got, tt.want) }
+	}
+}
+
+func TestClusters(t *testing.T) {
+	got := Clusters("a日👨‍👩‍👧é")
+	want := []Cluster{{"a", 1}, {"日", 2}, {"👨‍👩‍👧", 2}, {"é", 1}}
+	if !reflect.DeepEqual(got, want) { t.Errorf("Clusters = %q, want %q", got, want) }
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  string // lines joined by "|"
+	}{
+		{"fits", "hello world", 20, "hello world"},
+		{"words", "hello brave new world", 11, "hello brave|new world"},
+		{"trailing space overhangs", "abcde fghij", 5, "abcde|fghij"},
+		{"long word split", "abcdefghij", 4, "abcd|efgh|ij"},
+		{"newlines kept", "a\nb c", 0, "a|b c"},
+		{"CJK between ideographs", "日本語の文章", 5, "日本|語の|文章"},
+		{"CJK never splits a cluster", "日本語", 3, "日|本|語"},
+		{"ZWJ emoji stays whole", "👨‍👩‍👧👨‍👩‍👧", 3, "👨‍👩‍👧|👨‍👩‍👧"},
+		{"tabs expand before wrapping", "a\tb", 4, "a|b"},
+		{"empty", "", 10, ""},
+	}
+	m := NewMeasurer(0)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := m.Wrap(tt.s, tt.width)
+			if got := strings.Join(lines, "|"); got != tt.want { t.Errorf("Wrap(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want) }
+			for _, l := range lines {
+				if tt.width > 0 && StringWidth(l) > tt.width { t.Errorf("line %q wider than %d", l, tt.width) }
+			}
+		})
+	}
+}
+
+func TestMeasurerCache(t *testing.T) {
+	m := NewMeasurer(2)
+	if w, h := m.Size("hello brave new world", 11); w != 11 || h != 2 { t.Errorf("Size = %dx%d, want 11x2", w, h) }
+	m.Size("a", 1)
+	if m.Len() != 2 { t.Errorf("Len = %d, want 2", m.Len()) }
+	m.Size("b", 1) // full: dropped wholesale, then stored
+	if m.Len() != 1 { t.Errorf("Len after overflow = %d, want 1", m.Len()) }
+}
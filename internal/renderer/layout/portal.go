@@ -0,0 +1,44 @@
+package layout
+
+import "github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+
+// Anchor tells where a portal is placed ("anchor" prop).
+type Anchor int
+
+const (
+	AnchorParent Anchor = iota // at the parent's content origin (dropdowns, tooltips)
+	AnchorRoot                 // at the root's origin
+	AnchorCenter               // centered in the root (modals)
+)
+
+// PropAnchor is a portal's Anchor.
+var PropAnchor = renderer.NewPropKey[Anchor]("anchor")
+
+// IsPortal reports whether n renders in a root-level layer above the tree
+// ("portal" prop) instead of inside its parent.
+//
+// A portal takes no space in its parent's flow. It is offset by its "x"/"y"
+// props from its anchor, sized by w/h (or its content), and kept inside the
+// root bounds so overlays never fall off screen.
+func IsPortal(n Node) bool { return renderer.PropPortal.Or(n, false) }
+
+// portal places a portal child of a node whose content box is parent.
+func (e *Engine) portal(n Node, parent, root Rect) Rect {
+	p := n.Props()
+	w, h := e.natural(n, root.W)
+	w, h = min(w, root.W), min(h, root.H)
+	x, _ := renderer.PropX.Lookup(p)
+	y, _ := renderer.PropY.Lookup(p)
+	a, _ := PropAnchor.Lookup(p)
+	switch a {
+	case AnchorRoot:
+		x, y = root.X+x, root.Y+y
+	case AnchorCenter:
+		x, y = root.X+(root.W-w)/2+x, root.Y+(root.H-h)/2+y
+	default:
+		x, y = parent.X+x, parent.Y+y
+	}
+	x = max(root.X, min(x, root.X+root.W-w))
+	y = max(root.Y, min(y, root.Y+root.H-h))
+	return Rect{X: x, Y: y, W: w, H: h}
+}
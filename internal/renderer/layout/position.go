@@ -0,0 +1,75 @@
+package layout
+
+import "github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+
+// Position is how a node is placed relative to the flex flow ("position").
+type Position int
+
+const (
+	Static   Position = iota // in flow (default)
+	Relative                 // in flow, then shifted by its offsets; siblings keep their places
+	Absolute                 // out of flow, placed by its offsets against the parent rect
+)
+
+// Offset props for positioned nodes. An unset offset is "auto"; 0 is a
+// real offset.
+var (
+	PropPosition = renderer.NewPropKey[Position]("position")
+	PropTop      = renderer.NewPropKey[int]("top")
+	PropRight    = renderer.NewPropKey[int]("right")
+	PropBottom   = renderer.NewPropKey[int]("bottom")
+	PropLeft     = renderer.NewPropKey[int]("left")
+)
+
+// PositionOf returns n's Position.
+func PositionOf(n Node) Position { return PropPosition.Or(n, Static) }
+
+// inFlow reports whether n takes part in its parent's flex layout.
+func inFlow(n Node) bool { return !IsPortal(n) && PositionOf(n) != Absolute }
+
+// relative shifts an in-flow rect by its node's offsets (left wins over
+// right, top over bottom).
+func relative(n Node, r Rect) Rect {
+	p := n.Props()
+	if l, ok := PropLeft.Lookup(p); ok {
+		r.X += l
+	} else if v, ok := PropRight.Lookup(p); ok {
+		r.X -= v
+	}
+	if t, ok := PropTop.Lookup(p); ok {
+		r.Y += t
+	} else if v, ok := PropBottom.Lookup(p); ok {
+		r.Y -= v
+	}
+	return r
+}
+
+// absolute places n against its parent's rect: offsets are measured from
+// the parent's edges (border included, so a badge can sit on a frame), both
+// offsets on an axis stretch the node between them, and with neither it
+// stays at the parent's content origin. content is the parent's content box.
+func (e *Engine) absolute(n Node, parent, content Rect) Rect {
+	p := n.Props()
+	l, hasL := PropLeft.Lookup(p)
+	rt, hasR := PropRight.Lookup(p)
+	t, hasT := PropTop.Lookup(p)
+	b, hasB := PropBottom.Lookup(p)
+
+	w, h := e.natural(n, parent.W)
+	ew, eh := preferred(p, parent.W, parent.H)
+	if eh > 0 { h = eh }
+	minW, maxW, minH, maxH := limits(p)
+	if hasL && hasR && ew == 0 { w = clamp(max(parent.W-l-rt, 0), minW, maxW) }
+	if hasT && hasB && eh == 0 { h = clamp(max(parent.H-t-b, 0), minH, maxH) }
+
+	r := Rect{X: content.X, Y: content.Y, W: w, H: h}
+	switch {
+	case hasL: r.X = parent.X + l
+	case hasR: r.X = parent.X + parent.W - rt - w
+	}
+	switch {
+	case hasT: r.Y = parent.Y + t
+	case hasB: r.Y = parent.Y + parent.H - b - h
+	}
+	return r
+}
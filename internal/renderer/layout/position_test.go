@@ -0,0 +1,77 @@
+package layout_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/layout"
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+)
+
+// rectOf lays root out in w×h and returns the rect of child id as "x,y wxh".
+func rectOf(root ui.Node, w, h int, id string) string {
+	r := layout.Compute(root, ui.Rect{W: w, H: h})[ui.Key("r/"+id)]
+	return fmt.Sprintf("%d,%d %dx%d", r.X, r.Y, r.W, r.H)
+}
+
+func TestRelativePosition(t *testing.T) {
+	rel := ui.WithPosition(ui.Relative)
+	tests := []struct {
+		name string
+		opts []ui.NodeOption
+		want string
+	}{
+		{"no offsets", []ui.NodeOption{rel}, "4,0 3x2"},
+		{"left", []ui.NodeOption{rel, ui.WithLeft(2)}, "6,0 3x2"},
+		{"right", []ui.NodeOption{rel, ui.WithRight(2)}, "2,0 3x2"},
+		{"left wins over right", []ui.NodeOption{rel, ui.WithLeft(1), ui.WithRight(3)}, "5,0 3x2"},
+		{"top", []ui.NodeOption{rel, ui.WithTop(3)}, "4,3 3x2"},
+		{"bottom", []ui.NodeOption{rel, ui.WithBottom(1)}, "4,-1 3x2"},
+		{"top wins over bottom", []ui.NodeOption{rel, ui.WithTop(1), ui.WithBottom(3)}, "4,1 3x2"},
+		{"zero left beats right", []ui.NodeOption{rel, ui.WithLeft(0), ui.WithRight(3)}, "4,0 3x2"},
+		{"static ignores offsets", []ui.NodeOption{ui.WithLeft(2), ui.WithTop(2)}, "4,0 3x2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := ui.Box("r", ui.WithChildren(
+				ui.Box("a", ui.WithSize(4, 2)),
+				ui.Box("b", append([]ui.NodeOption{ui.WithSize(3, 2)}, tt.opts...)...),
+				ui.Box("c", ui.WithSize(2, 2)),
+			))
+			if got := rectOf(root, 20, 6, "b"); got != tt.want { t.Errorf("b = %s, want %s", got, tt.want) }
+			// Siblings keep their places.
+			if got := rectOf(root, 20, 6, "c"); got != "7,0 2x2" { t.Errorf("c = %s, want 7,0 2x2", got) }
+		})
+	}
+}
+
+func TestAbsolutePosition(t *testing.T) {
+	abs := ui.WithPosition(ui.Absolute)
+	tests := []struct {
+		name string
+		opts []ui.NodeOption
+		want string
+	}{
+		{"unset stays at the content origin", []ui.NodeOption{abs}, "2,1 3x2"},
+		{"zero offsets are the parent's edges", []ui.NodeOption{abs, ui.WithLeft(0), ui.WithTop(0)}, "0,0 3x2"},
+		{"left and top", []ui.NodeOption{abs, ui.WithLeft(4), ui.WithTop(2)}, "4,2 3x2"},
+		{"right and bottom", []ui.NodeOption{abs, ui.WithRight(1), ui.WithBottom(1)}, "16,7 3x2"},
+		{"zero right and bottom", []ui.NodeOption{abs, ui.WithRight(0), ui.WithBottom(0)}, "17,8 3x2"},
+		{"stretch across", []ui.NodeOption{abs, ui.WithLeft(2), ui.WithRight(3), ui.WithTop(1)}, "2,1 15x2"},
+		{"stretch down", []ui.NodeOption{abs, ui.WithTop(1), ui.WithBottom(2), ui.WithLeft(0)}, "0,1 3x7"},
+		{"stretch with zero offsets", []ui.NodeOption{abs, ui.WithLeft(0), ui.WithRight(0), ui.WithTop(0), ui.WithBottom(0)}, "0,0 20x10"},
+		{"stretch capped by max", []ui.NodeOption{abs, ui.WithLeft(0), ui.WithRight(0), ui.WithMaxWidth(6)}, "0,1 6x2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Stretching needs no explicit size on that axis, so size b by its content.
+			root := ui.Box("r", ui.WithBorder(ui.Attr{}), ui.WithPadding(ui.Padding{L: 1}), ui.WithChildren(
+				ui.Box("a", ui.WithSize(4, 2)),
+				ui.Box("b", append([]ui.NodeOption{ui.WithChildren(ui.Box("fill", ui.WithSize(3, 2)))}, tt.opts...)...),
+			))
+			if got := rectOf(root, 20, 10, "b"); got != tt.want { t.Errorf("b = %s, want %s", got, tt.want) }
+			// Out of flow: a keeps the content origin.
+			if got := rectOf(root, 20, 10, "a"); got != "2,1 4x2" { t.Errorf("a = %s, want 2,1 4x2", got) }
+		})
+	}
+}
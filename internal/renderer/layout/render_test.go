@@ -0,0 +1,38 @@
+package layout_test
+
+import (
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui/uitest"
+)
+
+// TestRenderWideText checks measured widths carry through to the cells:
+// wide clusters take two columns and wrap as a unit.
+func TestRenderWideText(t *testing.T) {
+	s := uitest.Render(ui.Text("t", "日本語の文章 👨‍👩‍👧!", ui.Attr{}), 5, 4)
+	uitest.AssertText(t, s, 0, 0, "日本")
+	uitest.AssertText(t, s, 0, 1, "語の")
+	uitest.AssertText(t, s, 0, 2, "文章")
+	uitest.AssertText(t, s, 0, 3, "👨‍👩‍👧!")
+	if c := s.Cell(0, 3); c.Width != 2 { t.Errorf("emoji cell width = %d, want 2", c.Width) }
+	if c := s.Cell(4, 0); c.Text != " " { t.Errorf("cell after 日本 = %q, want blank", c.Text) }
+}
+
+func TestRenderTabs(t *testing.T) {
+	s := uitest.Render(ui.Text("t", "a\tb\n日\tc", ui.Attr{}), 12, 2)
+	uitest.AssertText(t, s, 0, 0, "a       b")
+	uitest.AssertText(t, s, 0, 1, "日      c")
+}
+
+// TestRenderSlashID checks a sibling whose ID contains '/' doesn't hide the
+// nested node its key would otherwise collide with.
+func TestRenderSlashID(t *testing.T) {
+	tree := ui.Box("root", ui.WithDirection(ui.Column), ui.WithChildren(
+		ui.Box("a", ui.WithChildren(ui.Box("b", ui.WithChildren(ui.Text("x", "x", ui.Attr{}))))),
+		ui.Text("a/b", "y", ui.Attr{}),
+	))
+	s := uitest.Render(tree, 3, 2)
+	uitest.AssertText(t, s, 0, 0, "x")
+	uitest.AssertText(t, s, 0, 1, "y")
+}
@@ -0,0 +1,47 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+)
+
+// Relative sizing props. "w-pct"/"h-pct" size a node as a percentage of its
+// container's content box (w-full is 100); they win over "w"/"h". "aspect"
+// is width÷height in cells: with one side known it derives the other.
+var (
+	PropWPercent = renderer.NewPropKey[int]("w-pct")
+	PropHPercent = renderer.NewPropKey[int]("h-pct")
+	PropAspect   = renderer.NewPropKey[float64]("aspect")
+)
+
+// preferred returns n's preferred size in cells (0 = auto), resolving
+// percentages against availW/availH (<= 0 = unknown, leaving that side auto)
+// and filling a missing side from the aspect ratio.
+func preferred(p map[string]any, availW, availH int) (w, h int) {
+	w, _ = renderer.PropW.Lookup(p)
+	h, _ = renderer.PropH.Lookup(p)
+	if pct, ok := PropWPercent.Lookup(p); ok && availW > 0 { w = max(availW*pct/100, 1) }
+	if pct, ok := PropHPercent.Lookup(p); ok && availH > 0 { h = max(availH*pct/100, 1) }
+	if a, ok := PropAspect.Lookup(p); ok && a > 0 {
+		switch {
+		case w > 0 && h == 0: h = aspectH(w, a)
+		case h > 0 && w == 0: w = aspectW(h, a)
+		}
+	}
+	return w, h
+}
+
+// limits returns n's min/max width and height (max < 0 = unbounded).
+func limits(p map[string]any) (minW, maxW, minH, maxH int) {
+	minW, _ = renderer.PropMinW.Lookup(p)
+	minH, _ = renderer.PropMinH.Lookup(p)
+	maxW, ok := renderer.PropMaxW.Lookup(p); if !ok { maxW = -1 }
+	maxH, ok = renderer.PropMaxH.Lookup(p); if !ok { maxH = -1 }
+	return
+}
+
+func aspectOf(p map[string]any) float64 { a, _ := PropAspect.Lookup(p); return a }
+
+func aspectH(w int, a float64) int { return max(int(math.Round(float64(w)/a)), 1) }
+func aspectW(h int, a float64) int { return max(int(math.Round(float64(h)*a)), 1) }
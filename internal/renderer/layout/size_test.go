@@ -0,0 +1,46 @@
+package layout_test
+
+import (
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/layout"
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+)
+
+func TestRelativeSizes(t *testing.T) {
+	tests := []struct {
+		name string
+		root []ui.NodeOption // container, laid out in 20×10
+		opts []ui.NodeOption // child "a"
+		want string
+	}{
+		{"width percent", nil, []ui.NodeOption{ui.WithWidthPercent(50), ui.WithSize(0, 1)}, "0,0 10x1"},
+		{"percent beats cells", nil, []ui.NodeOption{ui.WithSize(5, 1), ui.WithWidthPercent(100)}, "0,0 20x1"},
+		{"height percent", nil, []ui.NodeOption{ui.WithSize(2, 0), ui.WithHeightPercent(30)}, "0,0 2x3"},
+		{"percent of the content box", []ui.NodeOption{ui.WithPadding(ui.Padding{L: 2, R: 2}), ui.WithBorder(ui.Attr{})}, []ui.NodeOption{ui.WithWidthPercent(50), ui.WithSize(0, 1)}, "3,1 7x1"},
+		{"percent floored by min", nil, []ui.NodeOption{ui.WithWidthPercent(10), ui.WithMinWidth(4), ui.WithSize(0, 1)}, "0,0 4x1"},
+		{"percent capped by max", nil, []ui.NodeOption{ui.WithWidthPercent(80), ui.WithMaxWidth(6), ui.WithSize(0, 1)}, "0,0 6x1"},
+		{"tiny percent is one cell", nil, []ui.NodeOption{ui.WithWidthPercent(1), ui.WithSize(0, 1)}, "0,0 1x1"},
+		{"aspect from width", nil, []ui.NodeOption{ui.WithSize(8, 0), ui.WithAspectRatio(2)}, "0,0 8x4"},
+		{"aspect from height", nil, []ui.NodeOption{ui.WithSize(0, 3), ui.WithAspectRatio(2)}, "0,0 6x3"},
+		{"aspect rounds", nil, []ui.NodeOption{ui.WithSize(5, 0), ui.WithAspectRatio(2)}, "0,0 5x3"},
+		{"aspect from a percent", nil, []ui.NodeOption{ui.WithWidthPercent(50), ui.WithAspectRatio(2.5)}, "0,0 10x4"},
+		{"aspect ignored with both sides", nil, []ui.NodeOption{ui.WithSize(4, 4), ui.WithAspectRatio(2)}, "0,0 4x4"},
+		{"column height percent", []ui.NodeOption{ui.WithDirection(ui.Column)}, []ui.NodeOption{ui.WithSize(3, 0), ui.WithHeightPercent(50)}, "0,0 3x5"},
+		{"absolute percent of the parent", nil, []ui.NodeOption{ui.WithPosition(ui.Absolute), ui.WithWidthPercent(25), ui.WithHeightPercent(50)}, "0,0 5x5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := ui.Box("r", append(append([]ui.NodeOption{ui.WithAlign(ui.AlignStart)}, tt.root...), ui.WithChildren(ui.Box("a", tt.opts...)))...)
+			if got := rectOf(root, 20, 10, "a"); got != tt.want { t.Errorf("a = %s, want %s", got, tt.want) }
+		})
+	}
+}
+
+func TestNestedPercent(t *testing.T) {
+	root := ui.Box("r", ui.WithAlign(ui.AlignStart), ui.WithChildren(
+		ui.Box("half", ui.WithWidthPercent(50), ui.WithSize(0, 1), ui.WithChildren(ui.Box("q", ui.WithWidthPercent(50), ui.WithSize(0, 1)))),
+	))
+	if got := rectOf(root, 20, 2, "half"); got != "0,0 10x1" { t.Errorf("half = %s, want 0,0 10x1", got) }
+	if got := layout.Compute(root, ui.Rect{W: 20, H: 2})["r/half/q"]; got != (ui.Rect{W: 5, H: 1}) { t.Errorf("q = %+v, want 5x1 (half of half)", got) }
+}
@@ -1 +1,81 @@
+// Package renderer is the private retained-mode engine behind pkg/ui.
+//
+// The scene-graph types live here so every engine stage (reconcile, layout,
+// raster, backend) can share them; pkg/ui re-exports them as aliases.
 package renderer
+
+// NodeID is a stable identity used by reconciliation.
+type NodeID string
+
+// Node is the declarative element in the scene graph.
+// - Immutable across frames (encouraged) for simpler diffing.
+// - Props is intentionally generic; keep it small (numbers, strings, bools).
+type Node interface {
+	ID() NodeID
+	Children() []Node
+	Props() map[string]any
+}
+
+// Attr are per-cell text attributes: foreground and background colors, the
+// SGR flags (bold, italic, dim, underline, strikethrough, reverse, blink)
+// and an underline's style and color.
+type Attr struct {
+	FG, BG    Color
+	Bold      bool
+	Underline bool
+
+	Italic, Dim, Strikethrough, Reverse, Blink bool
+
+	// UnderlineStyle and UnderlineColor refine Underline (terminals without
+	// styled underlines fall back to a plain one).
+	UnderlineStyle UnderlineStyle
+	UnderlineColor Color
+}
+
+// UnderlineStyle is the shape of an underline (SGR 4:n).
+type UnderlineStyle uint8
+
+const (
+	UnderlineSingle UnderlineStyle = iota
+	UnderlineDouble
+	UnderlineCurly
+	UnderlineDotted
+	UnderlineDashed
+)
+
+// DefaultAttr is the attribute of a blank cell: terminal colors, no styling.
+var DefaultAttr = Attr{}
+
+// Inherit fills the default colors of a from base, so nested nodes pick up
+// their ancestors' colors unless they set their own.
+func (a Attr) Inherit(base Attr) Attr {
+	if a.FG == DefaultColor { a.FG = base.FG }
+	if a.BG == DefaultColor { a.BG = base.BG }
+	return a
+}
+
+// Rect is a cell-space rectangle.
+type Rect struct{ X, Y, W, H int }
+
+// Empty reports whether r covers no cells.
+func (r Rect) Empty() bool { return r.W <= 0 || r.H <= 0 }
+
+// Intersect returns the overlap of r and o (zero Rect if disjoint).
+func (r Rect) Intersect(o Rect) Rect {
+	x0, y0 := max(r.X, o.X), max(r.Y, o.Y)
+	x1, y1 := min(r.X+r.W, o.X+o.W), min(r.Y+r.H, o.Y+o.H)
+	if x1 <= x0 || y1 <= y0 { return Rect{} }
+	return Rect{x0, y0, x1 - x0, y1 - y0}
+}
+
+// Union returns the smallest Rect covering both r and o.
+func (r Rect) Union(o Rect) Rect {
+	if r.Empty() { return o }
+	if o.Empty() { return r }
+	x0, y0 := min(r.X, o.X), min(r.Y, o.Y)
+	x1, y1 := max(r.X+r.W, o.X+o.W), max(r.Y+r.H, o.Y+o.H)
+	return Rect{x0, y0, x1 - x0, y1 - y0}
+}
+
+// Contains reports whether cell (x, y) lies inside r.
+func (r Rect) Contains(x, y int) bool { return x >= r.X && y >= r.Y && x < r.X+r.W && y < r.Y+r.H }
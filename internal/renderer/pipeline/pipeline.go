@@ -0,0 +1,125 @@
+// Package pipeline wires the engine stages together:
+// reconcile → layout → damage → raster → backend.
+package pipeline
+
+import (
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/backend"
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/layout"
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/raster"
+)
+
+type (
+	Node = renderer.Node
+	Rect = renderer.Rect
+)
+
+// Pipeline keeps the last committed tree and layout so each frame only
+// repaints what changed. It is not safe for concurrent use.
+type Pipeline struct {
+	Backend  backend.Backend
+	Layout   layout.Engine
+	Painter  raster.Painter
+	MaxRects int  // per-frame dirty rect cap (0 = raster.DefaultMaxRects)
+	Validate bool // check every planned tree's props (dev mode; see Problems)
+
+	prev     Node
+	prevLay  layout.Result
+	bounds   Rect
+	fresh    bool           // nothing committed at the current bounds yet
+	stale    []renderer.Key // repaint on the next Plan (see InvalidateKeys)
+	damage   *raster.Damage
+	stats    Stats
+	problems []renderer.PropError
+}
+
+// Stats describe the most recent frame (see README §10).
+type Stats struct {
+	Frames     int
+	Ops        int // cells written by the last plan
+	DirtyRects int
+	DirtyCells int // cells covered by the last plan's rects
+	Changes    int // reconciler changes in the last frame
+}
+
+// New returns a pipeline drawing into b, sharing one measurement cache
+// between layout and painting.
+func New(b backend.Backend) *Pipeline {
+	m := layout.NewMeasurer(4096)
+	return &Pipeline{
+		Backend: b,
+		Layout:  layout.Engine{Measure: m.Measure},
+		Painter: raster.Painter{Measurer: m},
+		fresh:   true,
+	}
+}
+
+// Plan diffs next against the last planned tree and returns the cell ops
+// for the regions that changed. A bounds change (resize) repaints everything.
+func (p *Pipeline) Plan(next Node, bounds Rect) renderer.RenderPlan {
+	w, h := bounds.X+bounds.W, bounds.Y+bounds.H
+	if bounds != p.bounds { p.bounds, p.fresh = bounds, true }
+	if bw, bh := p.Backend.Size(); bw != w || bh != h {
+		p.Backend.Resize(w, h)
+		p.fresh = true
+	}
+	if p.damage == nil { p.damage = raster.NewDamage(w, h, p.MaxRects) }
+	p.damage.Reset(w, h)
+
+	p.problems = nil
+	if p.Validate { p.problems = renderer.Validate(next) }
+	diff := renderer.Reconcile(p.prev, next)
+	lay := p.Layout.Layout(next, bounds)
+
+	if p.fresh {
+		p.damage.AddRect(bounds)
+	} else {
+		// Removed nodes leave their old area behind.
+		for _, k := range diff.Removed { p.damage.AddRect(p.prevLay[k]) }
+		for k, r := range lay {
+			old, existed := p.prevLay[k]
+			switch {
+			case !existed || old != r:
+				// Moved, resized or new: clear where it was, paint where it is.
+				p.damage.AddRect(old)
+				p.damage.AddRect(r)
+			case diff.Dirty.Has(k, renderer.DirtyPaint):
+				p.damage.AddRect(r)
+			}
+		}
+		for _, k := range p.stale { p.damage.AddRect(lay[k]) }
+	}
+
+	var plan renderer.RenderPlan
+	if !p.damage.Empty() {
+		plan.Dirty = p.damage.Rects()
+		plan.Ops = p.Painter.Paint(next, lay, bounds, plan.Dirty)
+	}
+	p.prev, p.prevLay, p.fresh, p.stale = next, lay, false, nil
+	p.stats.Frames++
+	p.stats.Ops, p.stats.DirtyRects, p.stats.DirtyCells, p.stats.Changes = len(plan.Ops), len(plan.Dirty), plan.Area(), len(diff.Changes)
+	return plan
+}
+
+// Apply writes plan into a new backend frame. The caller flushes.
+func (p *Pipeline) Apply(plan renderer.RenderPlan) {
+	p.Backend.BeginFrame()
+	for _, op := range plan.Ops {
+		if op.G != "" { p.Backend.PutCluster(op.X, op.Y, op.G, op.A) } else { p.Backend.PutCell(op.X, op.Y, op.R, op.A) }
+	}
+}
+
+// Invalidate makes the next Plan repaint everything.
+func (p *Pipeline) Invalidate() { p.fresh = true }
+
+// InvalidateKeys makes the next Plan repaint the nodes at keys even when
+// their props look unchanged, as when the planned tree was restyled in
+// place and the reconciler compares it against itself.
+func (p *Pipeline) InvalidateKeys(keys ...renderer.Key) { p.stale = append(p.stale, keys...) }
+
+// Problems returns the prop errors found in the last planned tree when
+// Validate is set.
+func (p *Pipeline) Problems() []renderer.PropError { return p.problems }
+
+// Stats returns counters for the most recent frame.
+func (p *Pipeline) Stats() Stats { return p.stats }
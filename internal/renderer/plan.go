@@ -0,0 +1,26 @@
+package renderer
+
+// CellOp is a primitive draw operation (renderer-internal but exposed for testing).
+type CellOp struct {
+	X, Y int
+	R    rune
+	G    string // full grapheme cluster when it is more than R (combining marks, ZWJ emoji)
+	A    Attr
+}
+
+// RenderPlan is what a reconciler/rasterizer produces for Commit.
+type RenderPlan struct {
+	Ops []CellOp
+	// Dirty are the screen areas this frame repaints; Ops never fall outside
+	// them. A bounded number of rects keeps a spinner in one corner from
+	// costing a full-screen repaint.
+	Dirty []Rect
+}
+
+// Area returns the number of cells covered by the dirty rects (overlaps
+// counted once per rect).
+func (p RenderPlan) Area() int {
+	n := 0
+	for _, r := range p.Dirty { n += r.W * r.H }
+	return n
+}
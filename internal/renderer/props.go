@@ -0,0 +1,163 @@
+package renderer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PropKey names a prop whose value has type T. Keys are registered when
+// created, so Validate can flag unknown names and values of the wrong type.
+type PropKey[T any] struct{ name string }
+
+// NewPropKey registers and returns the key for name. Registering the same
+// name twice with different types panics; custom keys should be prefixed
+// (e.g. "data-role") to stay clear of the engine's.
+func NewPropKey[T any](name string) PropKey[T] {
+	t := reflect.TypeFor[T]()
+	regMu.Lock()
+	defer regMu.Unlock()
+	if old, ok := registry[name]; ok && old != t { panic(fmt.Sprintf("renderer: prop %q registered as %v and %v", name, old, t)) }
+	registry[name] = t
+	return PropKey[T]{name}
+}
+
+// Name returns the prop's map key.
+func (k PropKey[T]) Name() string { return k.name }
+
+// Get reads the prop from n; ok is false when it is unset or of another type.
+func (k PropKey[T]) Get(n Node) (v T, ok bool) { return k.Lookup(n.Props()) }
+
+// Lookup reads the prop from a props map.
+func (k PropKey[T]) Lookup(p map[string]any) (v T, ok bool) {
+	v, ok = p[k.name].(T)
+	return
+}
+
+// Or reads the prop from n, returning def when it is unset.
+func (k PropKey[T]) Or(n Node, def T) T {
+	if v, ok := k.Get(n); ok { return v }
+	return def
+}
+
+// Has reports whether n sets the prop (to a value of the right type).
+func (k PropKey[T]) Has(n Node) bool { _, ok := k.Get(n); return ok }
+
+// Set stores v in a props map.
+func (k PropKey[T]) Set(p map[string]any, v T) { p[k.name] = v }
+
+var (
+	regMu    sync.RWMutex
+	registry = map[string]reflect.Type{}
+)
+
+// Padding is box padding in cells (the "padding" prop).
+type Padding = struct{ T, R, B, L int }
+
+// Props the engine reads; layout adds "direction" and "anchor".
+var (
+	PropText    = NewPropKey[string]("text")
+	PropAttr    = NewPropKey[Attr]("attr")
+	PropBorder  = NewPropKey[Attr]("border")
+	PropRadius  = NewPropKey[int]("radius")
+	PropPadding = NewPropKey[Padding]("padding")
+	PropW       = NewPropKey[int]("w")
+	PropH       = NewPropKey[int]("h")
+	PropMinW    = NewPropKey[int]("min-w")
+	PropMaxW    = NewPropKey[int]("max-w")
+	PropMinH    = NewPropKey[int]("min-h")
+	PropMaxH    = NewPropKey[int]("max-h")
+	PropGrow    = NewPropKey[int]("grow")
+	PropShrink  = NewPropKey[int]("shrink")
+	PropBasis   = NewPropKey[int]("basis")
+	PropGap     = NewPropKey[int]("gap")
+	PropWrap    = NewPropKey[bool]("wrap")
+	PropZ       = NewPropKey[int]("z")
+	PropPortal  = NewPropKey[bool]("portal")
+	PropX       = NewPropKey[int]("x")
+	PropY       = NewPropKey[int]("y")
+)
+
+// PropError is a prop Validate rejected.
+type PropError struct {
+	Key  Key    // node path
+	Prop string // prop name
+	Msg  string
+}
+
+// Error reads "<node path>: prop <name>: <problem>"; callers add their own
+// package prefix.
+func (e PropError) Error() string { return fmt.Sprintf("%s: prop %q: %s", e.Key, e.Prop, e.Msg) }
+
+// Validate walks root and reports props with unregistered names (except the
+// free-form "data-" and "aria-" namespaces) or values of the wrong type.
+// It is meant for development builds; the engine itself ignores bad props.
+func Validate(root Node) []PropError {
+	if root == nil { return nil }
+	var out []PropError
+	validate(&out, RootKey(root), root)
+	return out
+}
+
+func validate(out *[]PropError, k Key, n Node) {
+	p := n.Props()
+	names := make([]string, 0, len(p))
+	for name := range p { names = append(names, name) }
+	sort.Strings(names)
+	regMu.RLock()
+	for _, name := range names {
+		if strings.HasPrefix(name, "data-") || strings.HasPrefix(name, "aria-") { continue }
+		t, ok := registry[name]
+		switch {
+		case !ok:
+			msg := "unknown prop"
+			if s := suggest(name); s != "" { msg += fmt.Sprintf(" (did you mean %q?)", s) }
+			*out = append(*out, PropError{k, name, msg})
+		case reflect.TypeOf(p[name]) != t:
+			*out = append(*out, PropError{k, name, fmt.Sprintf("got %T, want %v", p[name], t)})
+		}
+	}
+	regMu.RUnlock()
+	kids := n.Children()
+	for i, ck := range ChildKeys(k, n) { validate(out, ck, kids[i]) }
+}
+
+// suggest returns the registered name closest to name (caller holds regMu),
+// or "" when nothing is within two edits.
+func suggest(name string) string {
+	names := make([]string, 0, len(registry))
+	for cand := range registry { names = append(names, cand) }
+	return Closest(name, names)
+}
+
+// Closest returns the candidate closest to name, or "" when nothing is
+// within two edits. Ties go to the alphabetically first candidate.
+func Closest(name string, cands []string) string {
+	best, bestD := "", 3
+	for _, cand := range cands {
+		if d := editDistance(name, cand); d < bestD || (d == bestD && cand < best) { best, bestD = cand, d }
+	}
+	return best
+}
+
+// editDistance is the optimal string alignment distance (Levenshtein plus
+// adjacent transpositions, so "gorw" is one edit from "grow").
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] { d[0][j] = j }
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] { cost = 0 }
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] { d[i][j] = min(d[i][j], d[i-2][j-2]+1) }
+		}
+	}
+	return d[len(a)][len(b)]
+}
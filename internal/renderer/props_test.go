@@ -0,0 +1,45 @@
+package renderer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tree := n("root",
+		n("a").with("gorw", 1),
+		n("b").with("grow", "1"),
+		n("c").with("grow", 1).with("data-role", "x").with("aria-label", 2),
+		n("d").with("zzzzzz", true),
+	)
+	want := []PropError{
+		{"root/a", "gorw", `unknown prop (did you mean "grow"?)`},
+		{"root/b", "grow", "got string, want int"},
+		{"root/d", "zzzzzz", "unknown prop"},
+	}
+	if got := Validate(tree); !reflect.DeepEqual(got, want) { t.Errorf("Validate =\n%v\nwant\n%v", got, want) }
+	if got := Validate(n("root").with("text", "hi")); got != nil { t.Errorf("Validate(clean) = %v", got) }
+	if got := want[0].Error(); got != `root/a: prop "gorw": unknown prop (did you mean "grow"?)` { t.Errorf("Error() = %s", got) }
+}
+
+func TestClosest(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"gorw", "grow"}, // transposition
+		{"gro", "grow"},  // deletion
+		{"paddin", "padding"},
+		{"tetx", "text"},
+		{"border-color", ""}, // too far from everything
+	}
+	cands := []string{"grow", "padding", "text", "border"}
+	for _, tt := range tests {
+		if got := Closest(tt.name, cands); got != tt.want { t.Errorf("Closest(%q) = %q, want %q", tt.name, got, tt.want) }
+	}
+	if got := Closest("ab", []string{"bb", "aa"}); got != "aa" { t.Errorf("tie went to %q, want the alphabetically first", got) }
+}
+
+func TestNewPropKeyTypeClash(t *testing.T) {
+	defer func() {
+		if recover() == nil { t.Error("re-registering \"grow\" as a string didn't panic") }
+	}()
+	NewPropKey[string]("grow")
+}
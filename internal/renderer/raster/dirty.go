@@ -1 +1,146 @@
-package raster
\ No newline at end of file
+// Package raster turns laid-out nodes into cell operations.
+package raster
+
+import "github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+
+type Rect = renderer.Rect
+
+// DefaultMaxRects is the per-frame rectangle cap used when none is given.
+const DefaultMaxRects = 16
+
+// fullScreenRatio: once this fraction of the surface is dirty, a single
+// full-surface rect is cheaper than tracking fragments.
+const fullScreenRatio = 0.6
+
+// Damage accumulates the changed cells of a W×H surface and packs them into
+// at most MaxRects rectangles. Packing may over-cover (a merged rect can
+// include clean cells) but never under-covers.
+type Damage struct {
+	W, H     int
+	MaxRects int
+	mask     []bool
+	count    int
+}
+
+// NewDamage returns an empty Damage for a w×h surface.
+func NewDamage(w, h, maxRects int) *Damage {
+	if maxRects <= 0 { maxRects = DefaultMaxRects }
+	return &Damage{W: w, H: h, MaxRects: maxRects, mask: make([]bool, max(w, 0)*max(h, 0))}
+}
+
+// Reset clears all damage, resizing the surface if needed.
+func (d *Damage) Reset(w, h int) {
+	if w != d.W || h != d.H || len(d.mask) != w*h {
+		d.W, d.H = w, h
+		d.mask = make([]bool, max(w, 0)*max(h, 0))
+	} else {
+		clear(d.mask)
+	}
+	d.count = 0
+}
+
+// AddCell marks one cell dirty; out-of-range cells are ignored.
+func (d *Damage) AddCell(x, y int) {
+	if x < 0 || y < 0 || x >= d.W || y >= d.H { return }
+	if i := y*d.W + x; !d.mask[i] { d.mask[i], d.count = true, d.count+1 }
+}
+
+// AddRect marks every cell of r (clipped to the surface) dirty.
+func (d *Damage) AddRect(r Rect) {
+	r = r.Intersect(Rect{W: d.W, H: d.H})
+	for y := r.Y; y < r.Y+r.H; y++ {
+		row := d.mask[y*d.W : (y+1)*d.W]
+		for x := r.X; x < r.X+r.W; x++ {
+			if !row[x] { row[x], d.count = true, d.count+1 }
+		}
+	}
+}
+
+// Dirty reports whether cell (x, y) is marked.
+func (d *Damage) Dirty(x, y int) bool {
+	return x >= 0 && y >= 0 && x < d.W && y < d.H && d.mask[y*d.W+x]
+}
+
+// Cells returns the number of dirty cells.
+func (d *Damage) Cells() int { return d.count }
+
+// Empty reports whether nothing is dirty.
+func (d *Damage) Empty() bool { return d.count == 0 }
+
+// Rects packs the dirty cells into at most MaxRects rectangles.
+func (d *Damage) Rects() []Rect {
+	if d.count == 0 { return nil }
+	if float64(d.count) >= fullScreenRatio*float64(d.W*d.H) { return []Rect{{W: d.W, H: d.H}} }
+
+	// 1. Horizontal runs per row, merged downwards while the span repeats.
+	var done []Rect
+	open := map[[2]int]int{} // span → index into done of a rect ending on the previous row
+	for y := 0; y < d.H; y++ {
+		row := d.mask[y*d.W : (y+1)*d.W]
+		next := map[[2]int]int{}
+		for x := 0; x < d.W; {
+			if !row[x] { x++; continue }
+			x0 := x
+			for x < d.W && row[x] { x++ }
+			span := [2]int{x0, x}
+			if i, ok := open[span]; ok {
+				done[i].H++
+				next[span] = i
+			} else {
+				next[span] = len(done)
+				done = append(done, Rect{X: x0, Y: y, W: x - x0, H: 1})
+			}
+		}
+		open = next
+	}
+	return Pack(done, d.MaxRects)
+}
+
+// Pack merges rects until at most limit remain, each step fusing the pair whose
+// bounding box adds the fewest clean cells. Overlapping inputs are fine.
+func Pack(rects []Rect, limit int) []Rect {
+	if limit <= 0 { limit = DefaultMaxRects }
+	out := make([]Rect, 0, len(rects))
+	for _, r := range rects {
+		if !r.Empty() { out = append(out, r) }
+	}
+	// Greedy pair merging is quadratic per step; coarsen pathological inputs
+	// (e.g. a checkerboard) into per-row-band bounding boxes first.
+	for band := 2; len(out) > 8*limit; band *= 2 { out = coarsen(out, band) }
+	for len(out) > limit {
+		bi, bj, best := 0, 1, -1
+		for i := 0; i < len(out); i++ {
+			for j := i + 1; j < len(out); j++ {
+				if w := waste(out[i], out[j]); best < 0 || w < best { bi, bj, best = i, j, w }
+			}
+		}
+		out[bi] = out[bi].Union(out[bj])
+		out = append(out[:bj], out[bj+1:]...)
+	}
+	return out
+}
+
+// waste is the number of cells a merged rect would cover beyond its parts.
+func waste(a, b Rect) int {
+	u := a.Union(b)
+	o := a.Intersect(b)
+	return u.W*u.H - a.W*a.H - b.W*b.H + o.W*o.H
+}
+
+// coarsen replaces rects by one bounding box per horizontal band of height band.
+func coarsen(rects []Rect, band int) []Rect {
+	boxes := map[int]Rect{}
+	var order []int
+	for _, r := range rects {
+		k := r.Y / band
+		if b, ok := boxes[k]; ok {
+			boxes[k] = b.Union(r)
+		} else {
+			boxes[k] = r
+			order = append(order, k)
+		}
+	}
+	out := make([]Rect, 0, len(order))
+	for _, k := range order { out = append(out, boxes[k]) }
+	return out
+}
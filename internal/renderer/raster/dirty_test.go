@@ -0,0 +1,86 @@
+package raster
+
+import (
+	"reflect"
+	"testing"
+)
+
+// covers reports whether every cell of each rect in in lies in some rect of out.
+func covers(out, in []Rect) bool {
+	for _, r := range in {
+		for y := r.Y; y < r.Y+r.H; y++ {
+			for x := r.X; x < r.X+r.W; x++ {
+				hit := false
+				for _, o := range out {
+					if x >= o.X && y >= o.Y && x < o.X+o.W && y < o.Y+o.H { hit = true; break }
+				}
+				if !hit { return false }
+			}
+		}
+	}
+	return true
+}
+
+func TestPack(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    []Rect
+		limit int
+		want  []Rect
+	}{
+		{"under limit", []Rect{{X: 0, W: 2, H: 1}, {X: 10, Y: 5, W: 1, H: 1}}, 4, []Rect{{X: 0, W: 2, H: 1}, {X: 10, Y: 5, W: 1, H: 1}}},
+		{"drops empty", []Rect{{W: 0, H: 3}, {X: 1, W: 1, H: 1}}, 4, []Rect{{X: 1, W: 1, H: 1}}},
+		{"cheapest pair merges", []Rect{{X: 0, W: 2, H: 1}, {X: 3, W: 2, H: 1}, {X: 0, Y: 9, W: 2, H: 1}}, 2,
+			[]Rect{{X: 0, W: 5, H: 1}, {X: 0, Y: 9, W: 2, H: 1}}},
+		{"limit one", []Rect{{X: 1, Y: 1, W: 1, H: 1}, {X: 4, Y: 3, W: 2, H: 1}}, 1, []Rect{{X: 1, Y: 1, W: 5, H: 3}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Pack(tt.in, tt.limit); !reflect.DeepEqual(got, tt.want) { t.Errorf("Pack = %v, want %v", got, tt.want) }
+		})
+	}
+}
+
+func TestPackCoarsensCheckerboard(t *testing.T) {
+	var in []Rect
+	for y := 0; y < 40; y++ {
+		for x := y % 2; x < 40; x += 2 { in = append(in, Rect{X: x, Y: y, W: 1, H: 1}) }
+	}
+	for _, limit := range []int{1, 4, 16} {
+		out := Pack(in, limit)
+		if len(out) > limit { t.Errorf("limit %d: got %d rects", limit, len(out)) }
+		if !covers(out, in) { t.Errorf("limit %d: %v leaves dirty cells uncovered", limit, out) }
+	}
+	// Coarsening keeps one box per row band, so each packed rect spans whole bands.
+	if got := coarsen(in[:40], 2); !reflect.DeepEqual(got, []Rect{{X: 0, Y: 0, W: 40, H: 2}}) { t.Errorf("coarsen = %v", got) }
+}
+
+func TestDamageRects(t *testing.T) {
+	d := NewDamage(20, 10, 4)
+	if d.Rects() != nil { t.Fatal("empty damage has rects") }
+	d.AddRect(Rect{X: 2, Y: 1, W: 3, H: 3})
+	d.AddCell(10, 8)
+	d.AddCell(10, 8)
+	d.AddCell(-1, 0)
+	if d.Cells() != 10 { t.Errorf("Cells = %d, want 10", d.Cells()) }
+	want := []Rect{{X: 2, Y: 1, W: 3, H: 3}, {X: 10, Y: 8, W: 1, H: 1}}
+	if got := d.Rects(); !reflect.DeepEqual(got, want) { t.Errorf("Rects = %v, want %v", got, want) }
+
+	for y := 0; y < 10; y += 2 {
+		for x := 0; x < 20; x += 3 { d.AddCell(x, y) }
+	}
+	got := d.Rects()
+	if len(got) > 4 { t.Errorf("got %d rects, want at most 4", len(got)) }
+	var all []Rect
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 20; x++ {
+			if d.Dirty(x, y) { all = append(all, Rect{X: x, Y: y, W: 1, H: 1}) }
+		}
+	}
+	if !covers(got, all) { t.Errorf("Rects %v leave dirty cells uncovered", got) }
+
+	d.AddRect(Rect{W: 20, H: 7})
+	if got := d.Rects(); !reflect.DeepEqual(got, []Rect{{W: 20, H: 10}}) { t.Errorf("mostly dirty: Rects = %v, want full surface", got) }
+	d.Reset(20, 10)
+	if !d.Empty() { t.Error("Reset left damage") }
+}
@@ -1 +1,290 @@
 package raster
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/layout"
+)
+
+type (
+	Node = renderer.Node
+	Attr = renderer.Attr
+)
+
+// Border glyph sets, picked by the "radius" prop.
+var (
+	squareCorners  = [4]rune{'┌', '┐', '└', '┘'}
+	roundedCorners = [4]rune{'╭', '╮', '╰', '╯'}
+)
+
+const (
+	hLine = '─'
+	vLine = '│'
+)
+
+// Painter rasterizes Box and Text nodes into cell ops.
+//
+// Props read: "attr" (Attr; boxes only fill their background when set),
+// "padding", "border" (Attr), "radius" (int), "text" (string) and "z" (int:
+// siblings paint in ascending z, positioned ones above in-flow ones at the
+// same z, then in tree order). Portals (see layout.IsPortal) paint after the
+// whole tree, ordered by z. Default colors inherit from the enclosing box, so
+// a Text with default colors keeps its panel's background. Children are
+// clipped to the inside of their parent's border, except absolutely
+// positioned ones, which may overlap it.
+type Painter struct {
+	// Measurer wraps text; nil uses layout.DefaultMeasurer.
+	Measurer *layout.Measurer
+}
+
+// Paint rasterizes root, laid out by lay, onto a surface covering bounds and
+// returns ops for the cells inside dirty (all of bounds when dirty is nil).
+// Cells no node paints come out blank, which clears removed content.
+func (p *Painter) Paint(root Node, lay layout.Result, bounds Rect, dirty []Rect) []renderer.CellOp {
+	if dirty == nil { dirty = []Rect{bounds} }
+	c := newCanvas(bounds)
+	if root != nil {
+		var area Rect
+		for _, d := range dirty { area = area.Union(d) }
+		// One more column each side, so wide clusters straddling a dirty
+		// edge are on the canvas when ops widens a span over them.
+		clip := bounds.Intersect(Rect{X: area.X - 1, Y: area.Y, W: area.W + 2, H: area.H})
+		k := renderer.RootKey(root)
+		p.node(c, lay, k, root, clip, renderer.DefaultAttr)
+		// Portals are painted last, over everything, clipped only to the
+		// surface: by ascending z, then nesting depth, so at equal z a nested
+		// portal stacks above the portal it sits in, then tree order.
+		var layers []layer
+		collectPortals(&layers, k, root, renderer.DefaultAttr, 0)
+		sort.SliceStable(layers, func(i, j int) bool {
+			a, b := layers[i], layers[j]
+			if a.z != b.z { return a.z < b.z }
+			return a.depth < b.depth
+		})
+		for _, l := range layers {
+			// An overlay hides what is under it even without a background.
+			if r, ok := lay[l.k]; ok { c.fill(r.Intersect(clip), l.at) }
+			p.node(c, lay, l.k, l.n, clip, l.at)
+		}
+	}
+	return c.ops(dirty)
+}
+
+// layer is a portal subtree awaiting its turn to paint.
+type layer struct {
+	k     renderer.Key
+	n     Node
+	at    Attr // attributes inherited from where the portal sits in the tree
+	z     int
+	depth int // portals it is nested in
+}
+
+// collectPortals lists the portals below n in tree order; depth counts the
+// portals n is in (n included).
+func collectPortals(out *[]layer, k renderer.Key, n Node, inherited Attr, depth int) {
+	if a, ok := renderer.PropAttr.Get(n); ok { inherited = a.Inherit(inherited) }
+	kids := n.Children()
+	for i, ck := range renderer.ChildKeys(k, n) {
+		d := depth
+		if layout.IsPortal(kids[i]) { *out = append(*out, layer{ck, kids[i], inherited, zOf(kids[i]), d}); d++ }
+		collectPortals(out, ck, kids[i], inherited, d)
+	}
+}
+
+func zOf(n Node) int { return renderer.PropZ.Or(n, 0) }
+
+func (p *Painter) node(c *canvas, lay layout.Result, k renderer.Key, n Node, clip Rect, inherited Attr) {
+	r, ok := lay[k]
+	if !ok { return }
+	vis := r.Intersect(clip)
+	if vis.Empty() { return } // nothing of this subtree can show: children are clipped to r
+	props := n.Props()
+
+	at := inherited
+	if a, ok := renderer.PropAttr.Lookup(props); ok {
+		at = a.Inherit(inherited)
+		if _, isText := renderer.PropText.Lookup(props); !isText { c.fill(vis, at) }
+	}
+	if b, ok := renderer.PropBorder.Lookup(props); ok { p.border(c, r, vis, b.Inherit(at), renderer.PropRadius.Or(n, 0)) }
+
+	inner := r
+	if layout.HasBorder(n) { inner = Rect{X: r.X + 1, Y: r.Y + 1, W: r.W - 2, H: r.H - 2} }
+	inner = inner.Intersect(clip)
+
+	if text, ok := renderer.PropText.Lookup(props); ok { p.text(c, text, layout.Content(n, r), inner, at) }
+
+	kids := n.Children()
+	keys := renderer.ChildKeys(k, n)
+	for _, i := range stacking(kids) {
+		// Absolutely positioned kids may sit on the border; others stay inside it.
+		kclip := inner
+		if layout.PositionOf(kids[i]) == layout.Absolute { kclip = vis }
+		p.node(c, lay, keys[i], kids[i], kclip, at)
+	}
+}
+
+// stacking returns the indexes of the non-portal kids in paint order: by
+// ascending "z", then in-flow before positioned (relative or absolute), then
+// tree order.
+func stacking(kids []Node) []int {
+	order := make([]int, 0, len(kids))
+	sorted := true
+	for i, k := range kids {
+		if layout.IsPortal(k) { continue }
+		if len(order) > 0 && less(k, kids[order[len(order)-1]]) { sorted = false }
+		order = append(order, i)
+	}
+	if !sorted { sort.SliceStable(order, func(a, b int) bool { return less(kids[order[a]], kids[order[b]]) }) }
+	return order
+}
+
+func less(a, b Node) bool {
+	if za, zb := zOf(a), zOf(b); za != zb { return za < zb }
+	return layout.PositionOf(a) == layout.Static && layout.PositionOf(b) != layout.Static
+}
+
+func (p *Painter) border(c *canvas, r, clip Rect, a Attr, radius int) {
+	if r.W < 2 || r.H < 2 { return }
+	corners := squareCorners
+	if radius >= 1 { corners = roundedCorners }
+	x0, y0, x1, y1 := r.X, r.Y, r.X+r.W-1, r.Y+r.H-1
+	for x := x0 + 1; x < x1; x++ {
+		c.put(clip, x, y0, hLine, "", 1, a)
+		c.put(clip, x, y1, hLine, "", 1, a)
+	}
+	for y := y0 + 1; y < y1; y++ {
+		c.put(clip, x0, y, vLine, "", 1, a)
+		c.put(clip, x1, y, vLine, "", 1, a)
+	}
+	c.put(clip, x0, y0, corners[0], "", 1, a)
+	c.put(clip, x1, y0, corners[1], "", 1, a)
+	c.put(clip, x0, y1, corners[2], "", 1, a)
+	c.put(clip, x1, y1, corners[3], "", 1, a)
+}
+
+func (p *Painter) text(c *canvas, text string, content, clip Rect, a Attr) {
+	m := p.Measurer
+	if m == nil { m = layout.DefaultMeasurer }
+	clip = clip.Intersect(content)
+	for i, line := range m.Wrap(text, content.W) {
+		y := content.Y + i
+		if y >= clip.Y+clip.H { return }
+		x := content.X
+		for _, cl := range layout.Clusters(line) {
+			if cl.Width == 0 { continue }
+			if x+cl.Width > clip.X+clip.W {
+				// A wide cluster cut by the clip edge shows as blank.
+				c.put(clip, x, y, ' ', "", 1, a.Inherit(c.at(x, y)))
+				break
+			}
+			first, g := splitCluster(cl.Text)
+			c.put(clip, x, y, first, g, cl.Width, a.Inherit(c.at(x, y)))
+			x += cl.Width
+		}
+	}
+}
+
+// splitCluster returns the first rune and, if there are more, the full cluster.
+func splitCluster(s string) (rune, string) {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == len(s) { return r, "" }
+	return r, s
+}
+
+// canvas is the scratch surface a frame is painted on.
+type canvas struct {
+	bounds Rect
+	cells  []cell
+}
+
+type cell struct {
+	r rune
+	g string
+	w int8 // 0 = right half of a wide cluster
+	a Attr
+}
+
+var blank = cell{r: ' ', w: 1, a: renderer.DefaultAttr}
+
+func newCanvas(b Rect) *canvas {
+	c := &canvas{bounds: b, cells: make([]cell, max(b.W, 0)*max(b.H, 0))}
+	for i := range c.cells { c.cells[i] = blank }
+	return c
+}
+
+func (c *canvas) index(x, y int) (int, bool) {
+	if !c.bounds.Contains(x, y) { return 0, false }
+	return (y-c.bounds.Y)*c.bounds.W + x - c.bounds.X, true
+}
+
+// at returns the attribute currently painted at (x, y).
+func (c *canvas) at(x, y int) Attr {
+	if i, ok := c.index(x, y); ok { return c.cells[i].a }
+	return renderer.DefaultAttr
+}
+
+func (c *canvas) fill(r Rect, a Attr) {
+	r = r.Intersect(c.bounds)
+	if r.Empty() { return }
+	for y := r.Y; y < r.Y+r.H; y++ {
+		c.erase(r.X, y)
+		c.erase(r.X+r.W-1, y)
+		for x := r.X; x < r.X+r.W; x++ {
+			i, _ := c.index(x, y)
+			c.cells[i] = cell{r: ' ', w: 1, a: a}
+		}
+	}
+}
+
+func (c *canvas) put(clip Rect, x, y int, r rune, g string, w int, a Attr) {
+	if !clip.Contains(x, y) || (w == 2 && !clip.Contains(x+1, y)) { return }
+	i, ok := c.index(x, y)
+	if !ok { return }
+	c.erase(x, y)
+	if w == 2 { c.erase(x+1, y) }
+	c.cells[i] = cell{r: r, g: g, w: int8(w), a: a}
+	if w == 2 {
+		if j, ok := c.index(x+1, y); ok { c.cells[j] = cell{w: 0, a: a} }
+	}
+}
+
+// erase blanks the other half of the wide cluster covering (x, y), if any,
+// so painting over either half never leaves an orphan behind.
+func (c *canvas) erase(x, y int) {
+	i, ok := c.index(x, y)
+	if !ok { return }
+	other := x + 1
+	switch c.cells[i].w {
+	case 0:
+		other = x - 1
+	case 2:
+	default:
+		return
+	}
+	if j, ok := c.index(other, y); ok { c.cells[j] = cell{r: ' ', w: 1, a: c.cells[j].a} }
+}
+
+// ops emits the cells inside dirty, each at most once. A span starting on
+// the right half of a wide cluster is widened to include its left half.
+func (c *canvas) ops(dirty []Rect) []renderer.CellOp {
+	var out []renderer.CellOp
+	seen := make([]bool, len(c.cells))
+	for _, d := range dirty {
+		d = d.Intersect(c.bounds)
+		for y := d.Y; y < d.Y+d.H; y++ {
+			x0 := d.X
+			if i, _ := c.index(x0, y); c.cells[i].w == 0 && x0 > c.bounds.X { x0-- }
+			for x := x0; x < d.X+d.W; x++ {
+				i, _ := c.index(x, y)
+				if seen[i] { continue }
+				seen[i] = true
+				cl := c.cells[i]
+				if cl.w == 0 { continue }
+				out = append(out, renderer.CellOp{X: x, Y: y, R: cl.r, G: cl.g, A: cl.a})
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,194 @@
+package raster_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/layout"
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/raster"
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui/uitest"
+)
+
+// paint lays root out in w×h and paints the cells inside dirty (nil for
+// all). It returns the ops and the rows they draw, '.' for cells no op
+// covers.
+func paint(t *testing.T, root ui.Node, w, h int, dirty []ui.Rect) ([]renderer.CellOp, []string) {
+	t.Helper()
+	bounds := ui.Rect{W: w, H: h}
+	ops := (&raster.Painter{}).Paint(root, layout.Compute(root, bounds), bounds, dirty)
+	grid := make([][]string, h)
+	for y := range grid { grid[y] = strings.Split(strings.Repeat(".", w), "") }
+	seen := map[[2]int]bool{}
+	for _, op := range ops {
+		if seen[[2]int{op.X, op.Y}] { t.Errorf("cell %d,%d painted twice", op.X, op.Y) }
+		seen[[2]int{op.X, op.Y}] = true
+		g := string(op.R)
+		if op.G != "" { g = op.G }
+		grid[op.Y][op.X] = g
+		if layout.Clusters(g)[0].Width == 2 { grid[op.Y][op.X+1] = "" }
+	}
+	rows := make([]string, h)
+	for y := range grid { rows[y] = strings.Join(grid[y], "") }
+	return ops, rows
+}
+
+func TestPaintBox(t *testing.T) {
+	red := ui.Attr{BG: ui.ANSI(1)}
+	tests := []struct {
+		name string
+		opts []ui.NodeOption
+		want []string
+	}{
+		{"square border", []ui.NodeOption{ui.WithBorder(ui.Attr{})}, []string{"┌────┐", "│    │", "└────┘"}},
+		{"rounded border", []ui.NodeOption{ui.WithBorder(ui.Attr{}), ui.WithRadius(1)}, []string{"╭────╮", "│    │", "╰────╯"}},
+		{"no border", nil, []string{"      ", "      ", "      "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := ui.Box("r", ui.WithChildren(ui.Box("box", append([]ui.NodeOption{ui.WithSize(6, 3), ui.WithAttr(red)}, tt.opts...)...)))
+			ops, rows := paint(t, root, 8, 3, nil)
+			if got := strings.Join(rows, "\n"); got != strings.Join(tt.want, "  \n")+"  " { t.Errorf("got\n%s\nwant\n%s", got, strings.Join(tt.want, "\n")) }
+			for _, op := range ops {
+				inBox := op.X < 6
+				if got := op.A.BG; (got == red.BG) != inBox { t.Errorf("cell %d,%d has bg %v; the box fills only its own rect", op.X, op.Y, got) }
+			}
+		})
+	}
+}
+
+func TestPaintBoxWithoutAttrKeepsParentFill(t *testing.T) {
+	blue := ui.Attr{BG: ui.ANSI(4)}
+	root := ui.Box("r", ui.WithAttr(blue), ui.WithChildren(ui.Box("inner", ui.WithSize(2, 1))))
+	ops, _ := paint(t, root, 3, 1, nil)
+	for _, op := range ops {
+		if op.A.BG != blue.BG { t.Errorf("cell %d,%d lost the parent's bg: %+v", op.X, op.Y, op.A) }
+	}
+}
+
+func TestPaintText(t *testing.T) {
+	tests := []struct {
+		name string
+		root ui.Node
+		want []string
+	}{
+		{"wraps at word breaks", ui.Text("t", "hello world", ui.Attr{}, ui.WithSize(5, 2)), []string{"hello ", "world "}},
+		{"clipped by its height", ui.Text("t", "one two three", ui.Attr{}, ui.WithSize(5, 2)), []string{"one   ", "two   "}},
+		{"clipped by a bordered parent", ui.Box("r", ui.WithChildren(ui.Box("p", ui.WithBorder(ui.Attr{}), ui.WithSize(5, 3), ui.WithChildren(
+			ui.Text("t", "abcdef", ui.Attr{}, ui.WithSize(6, 1)),
+		)))), []string{"┌───┐ ", "│abc│ ", "└───┘ "}},
+		{"wide cluster cut at the edge", ui.Box("r", ui.WithChildren(ui.Box("p", ui.WithSize(4, 1), ui.WithChildren(
+			ui.Text("t", "a日本", ui.Attr{}, ui.WithSize(6, 1)),
+		)))), []string{"a日   "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, rows := paint(t, tt.root, 6, len(tt.want), nil)
+			for y, want := range tt.want {
+				if rows[y] != want { t.Errorf("row %d = %q, want %q", y, rows[y], want) }
+			}
+		})
+	}
+}
+
+func TestPaintOnlyDirtyCells(t *testing.T) {
+	root := ui.Box("r", ui.WithDirection(ui.Column), ui.WithChildren(
+		ui.Text("a", "abcdef", ui.Attr{}),
+		ui.Text("b", "日本語", ui.Attr{}),
+	))
+	tests := []struct {
+		name  string
+		dirty []ui.Rect
+		want  []string
+	}{
+		{"one rect", []ui.Rect{{X: 1, Y: 0, W: 2, H: 1}}, []string{".bc...", "......"}},
+		{"overlapping rects", []ui.Rect{{X: 0, Y: 0, W: 3, H: 1}, {X: 2, Y: 0, W: 2, H: 1}}, []string{"abcd..", "......"}},
+		{"clipped to bounds", []ui.Rect{{X: 4, Y: 1, W: 10, H: 10}}, []string{"......", "....語"}},
+		{"widened over a wide cluster", []ui.Rect{{X: 3, Y: 1, W: 1, H: 1}}, []string{"......", "..本.."}},
+		{"ending on a wide cluster", []ui.Rect{{X: 0, Y: 1, W: 3, H: 1}}, []string{"......", "日本.."}},
+		{"none", []ui.Rect{}, []string{"......", "......"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, rows := paint(t, root, 6, 2, tt.dirty)
+			for y, want := range tt.want {
+				if rows[y] != want { t.Errorf("row %d = %q, want %q", y, rows[y], want) }
+			}
+		})
+	}
+}
+
+// overlay is a portal at the root's origin showing label.
+func overlay(id, label string, z int, nested ...ui.Node) ui.Node {
+	kids := append([]ui.Node{ui.Text("label", label, ui.Attr{})}, nested...)
+	return ui.Portal(id, ui.WithAnchor(ui.AnchorRoot), ui.WithZ(z), ui.WithChildren(kids...))
+}
+
+func TestPortalStacking(t *testing.T) {
+	tests := []struct {
+		name    string
+		portals []ui.Node
+		want    string
+	}{
+		{"tree order", []ui.Node{overlay("a", "AAAA", 0), overlay("b", "BB", 0)}, "BBAA"},
+		{"z first", []ui.Node{overlay("a", "AAAA", 1), overlay("b", "BB", 0)}, "AAAA"},
+		{"nested above parent", []ui.Node{overlay("a", "AAAA", 0, overlay("b", "BB", 0))}, "BBAA"},
+		{"nested above later sibling", []ui.Node{overlay("a", "AAAA", 0, overlay("b", "BB", 0)), overlay("c", "CCC", 0)}, "BBCA"},
+		{"nested with lower z", []ui.Node{overlay("a", "AAAA", 0, overlay("b", "BB", -1))}, "AAAA"},
+		{"nested with higher z", []ui.Node{overlay("a", "AAAA", 2, overlay("b", "BB", 3)), overlay("c", "CCC", 2)}, "BBCA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := ui.Box("root", ui.WithChildren(append([]ui.Node{ui.Text("under", "......", ui.Attr{})}, tt.portals...)...))
+			s := uitest.Render(root, 6, 1)
+			uitest.AssertText(t, s, 0, 0, tt.want+"......"[len(tt.want):])
+		})
+	}
+}
+
+func TestIncrementalOverlap(t *testing.T) {
+	tests := []struct {
+		name        string
+		first, next ui.Node
+		want        string
+	}{
+		{
+			"narrow over left half",
+			ui.Box("root", ui.WithDirection(ui.Column), ui.WithChildren(ui.Text("k0", "xyz1", ui.Attr{}))),
+			ui.Box("root", ui.WithChildren(
+				ui.Text("k0", "a", ui.Attr{}, ui.WithPosition(ui.Relative), ui.WithLeft(1)),
+				ui.Text("k2", "日", ui.Attr{}),
+			)),
+			" a",
+		},
+		{
+			"wide over right half",
+			ui.Box("root", ui.WithChildren(ui.Text("k0", "abcde", ui.Attr{}))),
+			ui.Box("root", ui.WithChildren(
+				ui.Text("k0", "日", ui.Attr{}),
+				ui.Text("k1", "本", ui.Attr{}, ui.WithPosition(ui.Relative), ui.WithLeft(-1)),
+			)),
+			" 本",
+		},
+		{
+			"fill over right half",
+			ui.Box("root", ui.WithChildren(ui.Text("k0", "日本", ui.Attr{}))),
+			ui.Box("root", ui.WithChildren(
+				ui.Text("k0", "日本", ui.Attr{}),
+				ui.Box("cover", ui.WithAttr(ui.Attr{Reverse: true}), ui.WithSize(2, 1), ui.WithPosition(ui.Absolute), ui.WithLeft(1)),
+			)),
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := uitest.New(5, 1)
+			s.Render(tt.first)
+			s.Render(tt.next)
+			fresh := uitest.Render(tt.next, 5, 1)
+			if got := s.Text(); got != tt.want { t.Errorf("incremental frame = %q, want %q", got, tt.want) }
+			if s.Frame() != fresh.Frame() { t.Errorf("incremental frame %q differs from a fresh render %q", s.Frame(), fresh.Frame()) }
+		})
+	}
+}
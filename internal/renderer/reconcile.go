@@ -1 +1,284 @@
 package renderer
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Key addresses a node by the path of IDs from the root, e.g. "root/toolbar/save".
+// IDs only need to be unique among siblings; the path makes them unique per tree.
+// Children without an ID (or with a duplicate one, or one containing '/' or
+// starting with '#') are keyed by position ("#3").
+type Key string
+
+// Child returns the key of a child with the given sibling-local id.
+func (k Key) Child(id string) Key {
+	if k == "" { return Key(id) }
+	return k + "/" + Key(id)
+}
+
+// Parent returns the key of the enclosing node ("" for the root).
+func (k Key) Parent() Key {
+	if i := strings.LastIndexByte(string(k), '/'); i >= 0 { return k[:i] }
+	return ""
+}
+
+// Op is the kind of structural change found by Reconcile.
+type Op uint8
+
+const (
+	OpInsert Op = iota + 1 // node mounted
+	OpRemove               // node unmounted
+	OpMove                 // same node, new sibling position
+	OpUpdate               // same node, props changed
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpInsert: return "insert"
+	case OpRemove: return "remove"
+	case OpMove:   return "move"
+	case OpUpdate: return "update"
+	}
+	return "op(" + strconv.Itoa(int(o)) + ")"
+}
+
+// Change is one entry of a Diff. A moved node whose props also changed
+// produces two changes (OpMove then OpUpdate).
+type Change struct {
+	Op       Op
+	Key      Key
+	Prev     Node     // nil for OpInsert
+	Next     Node     // nil for OpRemove
+	From, To int      // sibling index in prev / next tree (-1 when absent)
+	Props    []string // changed prop keys, sorted (OpUpdate only)
+}
+
+// Dirty flags describe what a downstream stage must redo for a node.
+type Dirty uint8
+
+const (
+	DirtyPaint    Dirty = 1 << iota // appearance changed; repaint its rect
+	DirtyLayout                     // size/position may change; re-run layout
+	DirtyChildren                   // child list changed (insert/remove/move)
+)
+
+// DirtySet maps next-tree keys to their dirty flags.
+type DirtySet map[Key]Dirty
+
+// Mark ORs f into the flags of k.
+func (d DirtySet) Mark(k Key, f Dirty) { d[k] |= f }
+
+// Has reports whether any of the flags in f are set for k.
+func (d DirtySet) Has(k Key, f Dirty) bool { return d[k]&f != 0 }
+
+// Diff is the result of reconciling two trees.
+type Diff struct {
+	Changes []Change
+	Dirty   DirtySet
+	// Removed lists every unmounted key (whole subtrees, parents first), so
+	// the rasterizer can clear the area they covered in the previous frame.
+	Removed []Key
+}
+
+// Empty reports whether the two trees were equivalent.
+func (d Diff) Empty() bool { return len(d.Changes) == 0 }
+
+// paintOnlyProps change how a node looks but never its size or position.
+// Every other prop (including unknown ones) is treated as layout-affecting.
+var paintOnlyProps = map[string]bool{PropAttr.Name(): true, PropRadius.Name(): true, PropZ.Name(): true}
+
+// Reconcile diffs prev against next. Children are matched by ID, not by
+// position, so reordering a list yields OpMove changes instead of a cascade
+// of updates. Either tree may be nil (first frame / teardown).
+func Reconcile(prev, next Node) Diff {
+	r := reconciler{diff: Diff{Dirty: DirtySet{}}}
+	var pk, nk Key
+	if prev != nil { pk = rootKey(prev) }
+	if next != nil { nk = rootKey(next) }
+	switch {
+	case prev == nil && next == nil:
+	case prev == nil:
+		r.insert(nk, next, -1)
+	case next == nil:
+		r.remove(pk, prev, -1)
+	case pk != nk || !sameKind(prev, next):
+		r.remove(pk, prev, -1)
+		r.insert(nk, next, -1)
+	default:
+		r.node(nk, prev, next)
+	}
+	return r.diff
+}
+
+type reconciler struct{ diff Diff }
+
+func rootKey(n Node) Key {
+	if !keyable(n.ID()) { return "#0" }
+	return Key(n.ID())
+}
+
+// sameKind reports whether two nodes share a concrete type; a Box replaced by
+// a Text under the same ID is a remount, not an update.
+func sameKind(a, b Node) bool { return reflect.TypeOf(a) == reflect.TypeOf(b) }
+
+func (r *reconciler) node(k Key, prev, next Node) {
+	if changed := diffProps(prev.Props(), next.Props()); len(changed) > 0 {
+		r.diff.Changes = append(r.diff.Changes, Change{Op: OpUpdate, Key: k, Prev: prev, Next: next, From: -1, To: -1, Props: changed})
+		var f Dirty
+		for _, p := range changed {
+			if paintOnlyProps[p] { f |= DirtyPaint } else { f |= DirtyPaint | DirtyLayout }
+		}
+		r.mark(k, f)
+	}
+	r.children(k, prev.Children(), next.Children())
+}
+
+func (r *reconciler) children(parent Key, prev, next []Node) {
+	pkeys := siblingKeys(parent, prev)
+	nkeys := siblingKeys(parent, next)
+	index := make(map[Key]int, len(prev))
+	for i, k := range pkeys { index[k] = i }
+
+	// Pair next children with their previous incarnation.
+	matched := make([]bool, len(prev))
+	from := make([]int, len(next))
+	for j, k := range nkeys {
+		from[j] = -1
+		if i, ok := index[k]; ok && sameKind(prev[i], next[j]) {
+			from[j], matched[i] = i, true
+		}
+	}
+
+	structural := false
+	for i, k := range pkeys {
+		if !matched[i] { r.remove(k, prev[i], i); structural = true }
+	}
+
+	// Children on the longest increasing run of old indices keep their
+	// relative order; everything else matched has moved.
+	stay := stableSet(from)
+	for j, k := range nkeys {
+		i := from[j]
+		if i < 0 {
+			r.insert(k, next[j], j)
+			structural = true
+			continue
+		}
+		if !stay[j] {
+			r.diff.Changes = append(r.diff.Changes, Change{Op: OpMove, Key: k, Prev: prev[i], Next: next[j], From: i, To: j})
+			r.mark(k, DirtyLayout|DirtyPaint)
+			structural = true
+		}
+		r.node(k, prev[i], next[j])
+	}
+	if structural { r.mark(parent, DirtyChildren|DirtyLayout) }
+}
+
+func (r *reconciler) insert(k Key, n Node, at int) {
+	r.diff.Changes = append(r.diff.Changes, Change{Op: OpInsert, Key: k, Next: n, From: -1, To: at})
+	var walk func(k Key, n Node)
+	walk = func(k Key, n Node) {
+		r.diff.Dirty.Mark(k, DirtyPaint|DirtyLayout|DirtyChildren)
+		kids := n.Children()
+		for i, ck := range siblingKeys(k, kids) { walk(ck, kids[i]) }
+	}
+	walk(k, n)
+	r.bubble(k.Parent())
+}
+
+func (r *reconciler) remove(k Key, n Node, at int) {
+	r.diff.Changes = append(r.diff.Changes, Change{Op: OpRemove, Key: k, Prev: n, From: at, To: -1})
+	var walk func(k Key, n Node)
+	walk = func(k Key, n Node) {
+		r.diff.Removed = append(r.diff.Removed, k)
+		kids := n.Children()
+		for i, ck := range siblingKeys(k, kids) { walk(ck, kids[i]) }
+	}
+	walk(k, n)
+}
+
+// mark flags k and, if its size may have changed, bubbles DirtyLayout up to
+// the root since ancestors sized by their content may change too.
+func (r *reconciler) mark(k Key, f Dirty) {
+	r.diff.Dirty.Mark(k, f)
+	if f&DirtyLayout != 0 { r.bubble(k.Parent()) }
+}
+
+func (r *reconciler) bubble(k Key) {
+	for ; k != ""; k = k.Parent() {
+		if r.diff.Dirty.Has(k, DirtyLayout) { return }
+		r.diff.Dirty.Mark(k, DirtyLayout)
+	}
+}
+
+// siblingKeys derives child keys; empty, duplicate or unkeyable IDs fall
+// back to "#i".
+func siblingKeys(parent Key, kids []Node) []Key {
+	keys := make([]Key, len(kids))
+	seen := make(map[NodeID]bool, len(kids))
+	for i, c := range kids {
+		id := c.ID()
+		if !keyable(id) || seen[id] {
+			keys[i] = parent.Child("#" + strconv.Itoa(i))
+			continue
+		}
+		seen[id] = true
+		keys[i] = parent.Child(string(id))
+	}
+	return keys
+}
+
+// keyable reports whether id can be a key segment: a '/' would read as a
+// path separator and a leading '#' as a position.
+func keyable(id NodeID) bool {
+	return id != "" && !strings.HasPrefix(string(id), "#") && !strings.Contains(string(id), "/")
+}
+
+// ChildKeys returns the keys Reconcile assigns to the children of the node at k.
+func ChildKeys(k Key, n Node) []Key { return siblingKeys(k, n.Children()) }
+
+// RootKey returns the key Reconcile assigns to a root node.
+func RootKey(n Node) Key { return rootKey(n) }
+
+// stableSet marks the positions of seq (ignoring -1 entries) that lie on a
+// longest strictly increasing subsequence.
+func stableSet(seq []int) []bool {
+	stay := make([]bool, len(seq))
+	tails := []int{}                 // positions in seq, by subsequence length
+	prevPos := make([]int, len(seq)) // back-links to rebuild the sequence
+	for j, v := range seq {
+		prevPos[j] = -1
+		if v < 0 { continue }
+		l := sort.Search(len(tails), func(i int) bool { return seq[tails[i]] >= v })
+		if l > 0 { prevPos[j] = tails[l-1] }
+		if l == len(tails) { tails = append(tails, j) } else { tails[l] = j }
+	}
+	if len(tails) > 0 {
+		for j := tails[len(tails)-1]; j >= 0; j = prevPos[j] { stay[j] = true }
+	}
+	return stay
+}
+
+// diffProps returns the sorted keys whose values differ between a and b.
+func diffProps(a, b map[string]any) []string {
+	var out []string
+	for k, av := range a {
+		if bv, ok := b[k]; !ok || !propEqual(av, bv) { out = append(out, k) }
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok { out = append(out, k) }
+	}
+	sort.Strings(out)
+	return out
+}
+
+func propEqual(a, b any) bool {
+	switch a.(type) {
+	case nil, string, bool, int, float64:
+		return a == b
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,174 @@
+package renderer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type box struct {
+	id    NodeID
+	kids  []Node
+	props map[string]any
+}
+
+func (b *box) ID() NodeID            { return b.id }
+func (b *box) Children() []Node      { return b.kids }
+func (b *box) Props() map[string]any { return b.props }
+
+type text struct{ box }
+
+func n(id string, kids ...Node) *box { return &box{id: NodeID(id), kids: kids, props: map[string]any{}} }
+
+func (b *box) with(k string, v any) *box { b.props[k] = v; return b }
+
+// list is a root holding one child per id.
+func list(ids ...string) Node {
+	var kids []Node
+	for _, id := range ids { kids = append(kids, n(id)) }
+	return n("root", kids...)
+}
+
+// ops renders changes compactly: "move root/b 1→0".
+func ops(d Diff) string {
+	var out []string
+	for _, c := range d.Changes {
+		s := fmt.Sprintf("%s %s", c.Op, c.Key)
+		switch c.Op {
+		case OpMove: s += fmt.Sprintf(" %d→%d", c.From, c.To)
+		case OpUpdate: s += " " + strings.Join(c.Props, ",")
+		}
+		out = append(out, s)
+	}
+	return strings.Join(out, "; ")
+}
+
+func TestReconcileLists(t *testing.T) {
+	tests := []struct {
+		name       string
+		prev, next Node
+		want       string
+	}{
+		{"same", list("a", "b", "c"), list("a", "b", "c"), ""},
+		{"append", list("a", "b"), list("a", "b", "c"), "insert root/c"},
+		{"prepend", list("a", "b"), list("z", "a", "b"), "insert root/z"},
+		{"remove middle", list("a", "b", "c"), list("a", "c"), "remove root/b"},
+		{"swap", list("a", "b"), list("b", "a"), "move root/b 1→0"},
+		{"move last to front", list("a", "b", "c", "d"), list("d", "a", "b", "c"), "move root/d 3→0"},
+		{"reverse", list("a", "b", "c"), list("c", "b", "a"), "move root/c 2→0; move root/b 1→1"},
+		{"replace", list("a", "b"), list("a", "x"), "remove root/b; insert root/x"},
+		{"first frame", nil, list("a"), "insert root"},
+		{"teardown", list("a"), nil, "remove root"},
+		{"root id change", list("a"), n("other"), "remove root; insert other"},
+		{"kind change", n("root", n("a")), n("root", &text{*n("a")}), "remove root/a; insert root/a"},
+		{"prop change", n("root", n("a").with("text", "x")), n("root", n("a").with("text", "y")), "update root/a text"},
+		{"moved and changed", n("root", n("a"), n("b").with("z", 1)), n("root", n("b").with("z", 2), n("a")), "move root/b 1→0; update root/b z"},
+	}
+	// Ties keep the later element in place: swapping a and b moves b.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ops(Reconcile(tt.prev, tt.next)); got != tt.want { t.Errorf("changes = %q, want %q", got, tt.want) }
+		})
+	}
+}
+
+func TestReconcileDirty(t *testing.T) {
+	prev := n("root", n("panel", n("label").with(PropAttr.Name(), Attr{}), n("body")))
+	next := n("root", n("panel", n("label").with(PropAttr.Name(), Attr{Bold: true}), n("body")))
+	d := Reconcile(prev, next)
+	want := DirtySet{"root/panel/label": DirtyPaint}
+	if !reflect.DeepEqual(d.Dirty, want) { t.Errorf("paint-only change: dirty = %v, want %v", d.Dirty, want) }
+
+	next = n("root", n("panel", n("label").with(PropAttr.Name(), Attr{}).with("text", "longer"), n("body")))
+	d = Reconcile(prev, next)
+	want = DirtySet{"root/panel/label": DirtyPaint | DirtyLayout, "root/panel": DirtyLayout, "root": DirtyLayout}
+	if !reflect.DeepEqual(d.Dirty, want) { t.Errorf("layout change: dirty = %v, want %v", d.Dirty, want) }
+
+	d = Reconcile(prev, n("root", n("panel", n("body"))))
+	if !d.Dirty.Has("root/panel", DirtyChildren) { t.Errorf("removal: parent not DirtyChildren: %v", d.Dirty) }
+	if !reflect.DeepEqual(d.Removed, []Key{"root/panel/label"}) { t.Errorf("Removed = %v", d.Removed) }
+}
+
+func TestReconcileRemovedSubtree(t *testing.T) {
+	d := Reconcile(n("root", n("a", n("b", n("c")))), n("root"))
+	if want := []Key{"root/a", "root/a/b", "root/a/b/c"}; !reflect.DeepEqual(d.Removed, want) { t.Errorf("Removed = %v, want %v", d.Removed, want) }
+}
+
+func TestSiblingKeys(t *testing.T) {
+	got := siblingKeys("root", []Node{n("a"), n(""), n("a"), n("#x"), n("a/b")})
+	if want := []Key{"root/a", "root/#1", "root/#2", "root/#3", "root/#4"}; !reflect.DeepEqual(got, want) { t.Errorf("keys = %v, want %v", got, want) }
+	if k := RootKey(n("a/b")); k != "#0" { t.Errorf("RootKey(a/b) = %q, want #0", k) }
+}
+
+// TestSlashIDsKeepKeysUnique checks a sibling "a/b" doesn't share a key with
+// the child b of a.
+func TestSlashIDsKeepKeysUnique(t *testing.T) {
+	tree := n("root", n("a", n("b", n("x"))), n("a/b"))
+	seen := map[Key]bool{}
+	var walk func(k Key, n Node)
+	walk = func(k Key, n Node) {
+		if seen[k] { t.Errorf("key %q assigned twice", k) }
+		seen[k] = true
+		for i, ck := range ChildKeys(k, n) { walk(ck, n.Children()[i]) }
+	}
+	walk(RootKey(tree), tree)
+	d := Reconcile(tree, n("root", n("a", n("b", n("x")))))
+	if want := []Key{"root/#1"}; !reflect.DeepEqual(d.Removed, want) { t.Errorf("Removed = %v, want %v", d.Removed, want) }
+}
+
+func TestStableSet(t *testing.T) {
+	tests := []struct {
+		seq  []int
+		want string // positions kept in place
+	}{
+		{nil, ""},
+		{[]int{0, 1, 2}, "xxx"},
+		{[]int{2, 1, 0}, "..x"},
+		{[]int{3, 0, 1, 2}, ".xxx"},
+		{[]int{1, 2, 3, 0}, "xxx."},
+		{[]int{-1, 0, -1, 1}, ".x.x"},
+		{[]int{4, 0, 3, 1, 2}, ".x.xx"},
+	}
+	for _, tt := range tests {
+		var sb strings.Builder
+		for _, keep := range stableSet(tt.seq) { if keep { sb.WriteByte('x') } else { sb.WriteByte('.') } }
+		if got := sb.String(); got != tt.want { t.Errorf("stableSet(%v) = %s, want %s", tt.seq, got, tt.want) }
+	}
+}
+
+// TestStableSetIsLongest checks every permutation of 5 against brute force:
+// the kept positions must increase and be a longest increasing run.
+func TestStableSetIsLongest(t *testing.T) {
+	var perm func(p []int, k int)
+	perm = func(p []int, k int) {
+		if k == len(p) {
+			stay := stableSet(p)
+			last, kept := -1, 0
+			for j, keep := range stay {
+				if !keep { continue }
+				if p[j] <= last { t.Fatalf("stableSet(%v) = %v keeps a decreasing pair", p, stay) }
+				last, kept = p[j], kept+1
+			}
+			if want := lis(p); kept != want { t.Fatalf("stableSet(%v) keeps %d, longest run is %d", p, kept, want) }
+			return
+		}
+		for i := k; i < len(p); i++ {
+			p[k], p[i] = p[i], p[k]
+			perm(p, k+1)
+			p[k], p[i] = p[i], p[k]
+		}
+	}
+	perm([]int{0, 1, 2, 3, 4}, 0)
+}
+
+func lis(p []int) int {
+	best := make([]int, len(p))
+	out := 0
+	for i := range p {
+		best[i] = 1
+		for j := 0; j < i; j++ { if p[j] < p[i] { best[i] = max(best[i], best[j]+1) } }
+		out = max(out, best[i])
+	}
+	return out
+}
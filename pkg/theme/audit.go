@@ -0,0 +1,130 @@
+package theme
+
+import (
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+)
+
+// Level is a WCAG 2 conformance level.
+type Level uint8
+
+const (
+	AA  Level = iota + 1 // text 4.5:1, non-text 3:1
+	AAA                  // text 7:1, non-text 3:1
+)
+
+func (l Level) String() string {
+	if l == AAA { return "AAA" }
+	return "AA"
+}
+
+// Kind tells which WCAG contrast rule a Pair follows.
+type Kind uint8
+
+const (
+	TextPair Kind = iota // text (1.4.3 and 1.4.6)
+	UIPair               // borders and other non-text (1.4.11)
+)
+
+// Min is the contrast ratio k needs at level l.
+func (k Kind) Min(l Level) float64 {
+	switch {
+	case k == UIPair: return 3
+	case l == AAA: return 7
+	}
+	return 4.5
+}
+
+// Pair is a foreground/background combination that has to stay readable.
+// FG and BG are "#RRGGBB" values or color tokens ("text", "pink-60").
+type Pair struct {
+	Name   string
+	FG, BG string
+	Kind   Kind
+	// Advisory, when set, says why a failure is only reported: checks like
+	// "strawberry theme check" don't fail on it.
+	Advisory string
+}
+
+// Pairs lists the combinations t's roles are used in: text on bg and on
+// surface, primary-fg on primary (button labels), primary on bg (links and
+// ghost buttons), and both borders on bg. primary/bg is advisory: primary
+// is a fill color first, and the default pink is too light for text on
+// white by design.
+func (t Tokens) Pairs() []Pair {
+	c := t.Colors
+	return []Pair{
+		{"text/bg", c.Text, c.Bg, TextPair, ""},
+		{"text/surface", c.Text, c.Surface, TextPair, ""},
+		{"primary-fg/primary", c.PrimaryFg, c.Primary, TextPair, ""},
+		{"primary/bg", c.Primary, c.Bg, TextPair, "primary is a fill; use a darker shade for text"},
+		{"border/bg", t.Border.Normal, c.Bg, UIPair, ""},
+		{"border-focused/bg", t.Border.Focused, c.Bg, UIPair, ""},
+	}
+}
+
+// auditProfiles are the color depths Audit checks: colors are downsampled
+// to the xterm palette like the engines do, and the 16 basic colors use
+// xterm's defaults, which terminal themes may change.
+var auditProfiles = []ui.Profile{ui.TrueColor, ui.ANSI256, ui.ANSI16}
+
+// Result is the contrast of one Pair of one theme at one color depth.
+type Result struct {
+	Theme   string     // theme or variant name ("strawberry-dark")
+	Pair    Pair       // FG and BG resolved to hex
+	Profile ui.Profile // TrueColor, ANSI256 or ANSI16
+	FG, BG  ui.Color   // the colors as displayed under Profile
+	Ratio   float64
+}
+
+// Pass reports whether r meets level l.
+func (r Result) Pass(l Level) bool { return r.Ratio >= r.Pair.Kind.Min(l) }
+
+// Suggest returns the foreground nearest r's that meets level l under
+// r.Profile (see Suggest).
+func (r Result) Suggest(l Level) (ui.Color, bool) {
+	return Suggest(toTermColor(r.Pair.FG), toTermColor(r.Pair.BG), r.Pair.Kind.Min(l), r.Profile)
+}
+
+// Audit checks the contrast of th's Pairs, plus extra, for th and each of
+// its variants, at truecolor, 256 and 16 colors. Pairs whose colors don't
+// resolve to hex are skipped.
+//
+//	for _, r := range theme.Audit(theme.Default()) {
+//		if !r.Pass(theme.AA) { fix, _ := r.Suggest(theme.AA); ... }
+//	}
+func Audit(th Theme, extra ...Pair) []Result {
+	themes := []Theme{th}
+	for _, name := range keys(th.Variants) { themes = append(themes, th.Variant(name)) }
+	var out []Result
+	for _, t := range themes {
+		for _, p := range append(t.Tokens.Pairs(), extra...) {
+			p.FG, p.BG = t.Tokens.Color(p.FG), t.Tokens.Color(p.BG)
+			fg, bg := toTermColor(p.FG), toTermColor(p.BG)
+			if fg == ui.DefaultColor || bg == ui.DefaultColor { continue }
+			for _, prof := range auditProfiles {
+				dfg, dbg := fg.Downsample(prof), bg.Downsample(prof)
+				out = append(out, Result{t.Name, p, prof, dfg, dbg, renderer.Contrast(dfg, dbg)})
+			}
+		}
+	}
+	return out
+}
+
+// Suggest returns the color nearest fg that reaches contrast want against
+// bg once both are downsampled to p. It keeps fg's hue and chroma and moves
+// its OKLab lightness the least it can, in either direction; false means no
+// lightness does.
+func Suggest(fg, bg ui.Color, want float64, p ui.Profile) (ui.Color, bool) {
+	r, g, b, _ := fg.RGB()
+	L, A, B := renderer.OKLab(r, g, b)
+	for d := 0.0; d <= 1; d += 0.002 {
+		for _, l := range []float64{L + d, L - d} {
+			if l < 0 || l > 1 { continue }
+			c := ui.RGB(renderer.FromOKLab(l, A, B))
+			if renderer.Contrast(c.Downsample(p), bg.Downsample(p)) >= want { return c, true }
+		}
+	}
+	return fg, false
+}
+
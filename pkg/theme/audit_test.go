@@ -0,0 +1,72 @@
+package theme
+
+import (
+	"math"
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+)
+
+// floor2 truncates a ratio to the two decimals `theme check` prints.
+func floor2(r float64) float64 { return math.Floor(r*100) / 100 }
+
+func TestContrast(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"#000000", "#FFFFFF", 21},
+		{"#FFFFFF", "#000000", 21},
+		{"#777777", "#777777", 1},
+		{"#F4ACB7", "#FFFFFF", 1.83},
+		{"#767676", "#FFFFFF", 4.54},
+		{"#242423", "#FFFFFF", 15.53},
+	}
+	for _, tt := range tests {
+		a, b := toTermColor(tt.a), toTermColor(tt.b)
+		if got := renderer.Contrast(a, b); floor2(got) != tt.want { t.Errorf("Contrast(%s, %s) = %.3f, want %.2f", tt.a, tt.b, got, tt.want) }
+	}
+}
+
+func TestAuditDefault(t *testing.T) {
+	results := Audit(Default())
+	// 6 pairs × 3 profiles × (base, dark, high-contrast)
+	if len(results) != 54 { t.Errorf("got %d results, want 54", len(results)) }
+	// The light pink primary stays as designed: it is the one pair below
+	// AA, at every color depth, and only advisory.
+	fails := 0
+	for _, r := range results {
+		if r.Pass(AA) { continue }
+		fails++
+		if r.Theme != "strawberry" || r.Pair.Name != "primary/bg" || r.Pair.Advisory == "" { t.Errorf("%s %s %s: %s on %s is %.2f:1", r.Theme, r.Pair.Name, r.Profile, r.FG, r.BG, r.Ratio) }
+	}
+	if fails != 3 { t.Errorf("%d checks fail AA, want 3 (primary/bg per profile)", fails) }
+}
+
+func TestAuditRatiosAndSuggest(t *testing.T) {
+	th := Default()
+	th.Variants = nil
+	want := map[ui.Profile]float64{ui.TrueColor: 1.83, ui.ANSI256: 1.74, ui.ANSI16: 1.25}
+	seen := 0
+	for _, r := range Audit(th, Pair{Name: "muted/bg", FG: "muted", BG: "bg", Kind: TextPair}, Pair{Name: "ansi/bg", FG: "red", BG: "bg", Kind: TextPair}) {
+		if r.Pair.Name == "ansi/bg" { t.Errorf("pair with a non-hex color was audited") }
+		if r.Pair.Name != "primary/bg" { continue }
+		seen++
+		if r.Pair.FG != "#F4ACB7" || r.Pair.BG != "#FFFFFF" { t.Errorf("pair resolved to %s on %s", r.Pair.FG, r.Pair.BG) }
+		if floor2(r.Ratio) != want[r.Profile] || r.Pass(AA) { t.Errorf("%s: ratio %.3f, want failing %.2f", r.Profile, r.Ratio, want[r.Profile]) }
+		for _, l := range []Level{AA, AAA} {
+			fix, ok := r.Suggest(l)
+			if !ok { t.Errorf("%s %s: no suggestion", r.Profile, l); continue }
+			got := renderer.Contrast(fix.Downsample(r.Profile), r.BG)
+			if got < TextPair.Min(l) { t.Errorf("%s %s: suggestion %s only reaches %.2f:1", r.Profile, l, fix, got) }
+			// Darker, same hue.
+			_, _, h0 := renderer.OKLCH(0xF4, 0xAC, 0xB7)
+			fr, fg, fb, _ := fix.RGB()
+			if L, _, h := renderer.OKLCH(fr, fg, fb); L >= 0.8 || math.Abs(h-h0) > 5 { t.Errorf("%s %s: suggestion %s drifts (L %.2f, hue %.1f vs %.1f)", r.Profile, l, fix, L, h, h0) }
+		}
+	}
+	if seen != 3 { t.Errorf("audited primary/bg %d times, want 3", seen) }
+	if fix, _ := Suggest(toTermColor("#F4ACB7"), toTermColor("#FFFFFF"), 4.5, ui.TrueColor); fix.String() != "#a5646f" { t.Errorf("Suggest = %s, want #a5646f", fix) }
+	if _, ok := Suggest(toTermColor("#808080"), toTermColor("#808080"), 22, ui.TrueColor); ok { t.Error("Suggest met an impossible ratio") }
+}
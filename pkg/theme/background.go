@@ -0,0 +1,225 @@
+package theme
+
+import (
+	"context"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+// Appearance is the kind of terminal background a theme variant is for.
+type Appearance uint8
+
+const (
+	Light        Appearance = iota // "light": the base theme
+	Dark                           // "dark"
+	HighContrast                   // "high-contrast"
+)
+
+func (a Appearance) String() string {
+	switch a {
+	case Dark: return "dark"
+	case HighContrast: return "high-contrast"
+	}
+	return "light"
+}
+
+// ParseAppearance parses "light", "dark" or "high-contrast".
+func ParseAppearance(s string) (Appearance, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "light": return Light, true
+	case "dark": return Dark, true
+	case "high-contrast", "contrast", "hc": return HighContrast, true
+	}
+	return Light, false
+}
+
+// Appearance returns th's variant named after a ("dark", ...), or th when
+// it has none; Default has no "light" variant since it is light already.
+func (th Theme) Appearance(a Appearance) Theme { return th.Variant(a.String()) }
+
+// EnvAppearance overrides detection: light, dark or high-contrast.
+const EnvAppearance = "STRAWBERRY_APPEARANCE"
+
+// Detector finds the terminal's Appearance. The zero value is ready to use.
+type Detector struct {
+	Timeout time.Duration       // wait for the OSC 11 reply (default 200ms)
+	TTY     string              // terminal Detect queries (default /dev/tty)
+	Output  io.Writer           // where Watch writes queries (default os.Stdout, Bubble Tea's output)
+	Getenv  func(string) string // environment (default os.Getenv)
+}
+
+// DetectAppearance is Detector{}.Detect.
+func DetectAppearance() Appearance { return Detector{}.Detect() }
+
+// Detect tries, in order: $STRAWBERRY_APPEARANCE; the background color the
+// terminal reports for an OSC 11 query; the background index in
+// $COLORFGBG ("15;0"); and falls back to Light. Call it before the program
+// takes over the terminal, e.g. before tea.NewProgram:
+//
+//	th := theme.Default().Appearance(theme.DetectAppearance())
+func (d Detector) Detect() Appearance {
+	getenv := d.Getenv
+	if getenv == nil { getenv = os.Getenv }
+	if a, ok := ParseAppearance(getenv(EnvAppearance)); ok { return a }
+	if a, ok := d.query(); ok { return a }
+	if a, ok := colorFgBg(getenv("COLORFGBG")); ok { return a }
+	return Light
+}
+
+// AppearanceMsg reports that the terminal's background changed (see
+// BackgroundReader).
+type AppearanceMsg struct{ Appearance Appearance }
+
+// Watch returns a command that asks the terminal for its background color
+// every interval (default 2s) until ctx is done, so an app can follow the
+// terminal switching between light and dark. It only writes the OSC 11
+// query, to Output; the terminal answers on the program's input, where a
+// BackgroundReader picks the reply out:
+//
+//	ctx, cancel := context.WithCancel(context.Background()) // cancel on quit
+//	func (m model) Init() tea.Cmd { return m.detector.Watch(m.ctx, 0) }
+//
+// Watch returns nil when $STRAWBERRY_APPEARANCE is set, so the override
+// sticks.
+func (d Detector) Watch(ctx context.Context, every time.Duration) tea.Cmd {
+	getenv := d.Getenv
+	if getenv == nil { getenv = os.Getenv }
+	if _, ok := ParseAppearance(getenv(EnvAppearance)); ok { return nil }
+	if every <= 0 { every = 2 * time.Second }
+	out := d.Output
+	if out == nil { out = os.Stdout }
+	return func() tea.Msg {
+		t := time.NewTicker(every)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done(): return nil
+			case <-t.C:
+				// One write, so the query never lands inside a frame.
+				if _, err := io.WriteString(out, "\x1b]11;?\x07"); err != nil { return nil }
+			}
+		}
+	}
+}
+
+// BackgroundReader picks the replies to Watch's queries out of Bubble
+// Tea's input, which reports them as keys: alt+], the reply's body as
+// runes, then ctrl+g (or alt+\). Current is the appearance in use; set it
+// to what Detect returned.
+type BackgroundReader struct {
+	Current Appearance
+
+	open bool
+	body []rune
+}
+
+// Update feeds msg to r and reports whether msg was part of a reply, which
+// the app should then skip. Once a reply names another appearance than
+// Current, the returned command yields its AppearanceMsg:
+//
+//	case tea.KeyMsg:
+//		if cmd, ok := m.bg.Update(msg); ok { return m, cmd }
+//	case theme.AppearanceMsg:
+//		m.theme = theme.Default().Appearance(msg.Appearance)
+//
+// A real alt+] keypress is swallowed when it isn't followed by a reply.
+func (r *BackgroundReader) Update(msg tea.Msg) (tea.Cmd, bool) {
+	k, ok := msg.(tea.KeyMsg)
+	if !ok { return nil, false }
+	switch {
+	case !r.open:
+		if k.Type == tea.KeyRunes && k.Alt && string(k.Runes) == "]" { r.open, r.body = true, r.body[:0]; return nil, true }
+		return nil, false
+	case k.Type == tea.KeyRunes && !k.Alt:
+		// The body may come in pieces; it has to start with "11;".
+		r.body = append(r.body, k.Runes...)
+		b := string(r.body)
+		if strings.HasPrefix(b, "11;") || strings.HasPrefix("11;", b) { return nil, true }
+	case k.Type == tea.KeyCtrlG || (k.Type == tea.KeyRunes && k.Alt && string(k.Runes) == `\`):
+		r.open = false
+		a, ok := parseOSC11([]byte("\x1b]" + string(r.body)))
+		if !ok || a == r.Current { return nil, true }
+		r.Current = a
+		return func() tea.Msg { return AppearanceMsg{a} }, true
+	}
+	r.open = false
+	return nil, false
+}
+
+var (
+	osc11Reply = regexp.MustCompile(`\x1b\]11;rgba?:([0-9a-fA-F]{1,4})/([0-9a-fA-F]{1,4})/([0-9a-fA-F]{1,4})`)
+	da1Reply   = regexp.MustCompile(`\x1b\[\?[0-9;]*c`)
+)
+
+// query asks the terminal for its background color (OSC 11). A device
+// attributes request (DA1) follows it: every terminal answers DA1, and in
+// order, so its reply ends the wait early when OSC 11 is unsupported and
+// nothing of the answers is left for the program to read. Replies still
+// missing at the deadline get as long again to arrive and are discarded,
+// so they don't reach the program as keys. The query is only sent once
+// the terminal is in raw mode.
+func (d Detector) query() (Appearance, bool) {
+	tty, timeout := d.TTY, d.Timeout
+	if tty == "" { tty = "/dev/tty" }
+	if timeout <= 0 { timeout = 200 * time.Millisecond }
+	f, err := os.OpenFile(tty, os.O_RDWR, 0)
+	if err != nil { return Light, false }
+	defer f.Close()
+	// The deadline needs a pollable file; calling f.Fd() would make it blocking.
+	if f.SetReadDeadline(time.Now().Add(timeout)) != nil { return Light, false }
+	rc, err := f.SyscallConn()
+	if err != nil { return Light, false }
+	var state *term.State
+	var rawErr error
+	if err := rc.Control(func(fd uintptr) { state, rawErr = term.MakeRaw(int(fd)) }); err != nil || rawErr != nil || state == nil { return Light, false }
+	defer rc.Control(func(fd uintptr) { term.Restore(int(fd), state) })
+
+	if _, err := f.WriteString("\x1b]11;?\x07\x1b[c"); err != nil { return Light, false }
+	var buf []byte
+	chunk := make([]byte, 64)
+	read := func() {
+		for !da1Reply.Match(buf) {
+			n, err := f.Read(chunk)
+			buf = append(buf, chunk[:n]...)
+			if err != nil { return }
+		}
+	}
+	read()
+	a, ok := parseOSC11(buf)
+	if !da1Reply.Match(buf) && f.SetReadDeadline(time.Now().Add(timeout)) == nil { read() }
+	return a, ok
+}
+
+// parseOSC11 reads the background color out of an OSC 11 reply: dark when
+// its OKLab lightness is below one half.
+func parseOSC11(buf []byte) (Appearance, bool) {
+	m := osc11Reply.FindSubmatch(buf)
+	if m == nil { return Light, false }
+	r, g, b := scaleHex(m[1]), scaleHex(m[2]), scaleHex(m[3])
+	if l, _, _ := renderer.OKLab(r, g, b); l < 0.5 { return Dark, true }
+	return Light, true
+}
+
+// scaleHex maps an X11 color component of 1–4 hex digits to 0–255.
+func scaleHex(h []byte) uint8 {
+	v, _ := strconv.ParseUint(string(h), 16, 16)
+	full := uint64(1)<<(4*len(h)) - 1
+	return uint8((v*255 + full/2) / full)
+}
+
+// colorFgBg reads the background index of $COLORFGBG ("fg;bg" or
+// "fg;default;bg", set by rxvt and others): 0–6 and 8 are dark colors.
+func colorFgBg(v string) (Appearance, bool) {
+	n, err := strconv.Atoi(v[strings.LastIndexByte(v, ';')+1:])
+	if err != nil || n < 0 || n > 15 || !strings.Contains(v, ";") { return Light, false }
+	if n < 7 || n == 8 { return Dark, true }
+	return Light, true
+}
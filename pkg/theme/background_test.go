@@ -0,0 +1,157 @@
+package theme
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseOSC11(t *testing.T) {
+	tests := []struct {
+		reply string
+		want  Appearance
+		ok    bool
+	}{
+		{"\x1b]11;rgb:0000/0000/0000\x07", Dark, true},
+		{"\x1b]11;rgb:ffff/ffff/ffff\x1b\\", Light, true},
+		{"\x1b]11;rgb:2424/2424/2323", Dark, true},
+		{"\x1b]11;rgba:ff/ca/d4/ff", Light, true},
+		{"\x1b]11;rgb:f/f/f", Light, true},
+		{"\x1b]10;rgb:0000/0000/0000", Light, false},
+		{"\x1b]11;?", Light, false},
+	}
+	for _, tt := range tests {
+		if a, ok := parseOSC11([]byte(tt.reply)); a != tt.want || ok != tt.ok { t.Errorf("parseOSC11(%q) = %v, %v; want %v, %v", tt.reply, a, ok, tt.want, tt.ok) }
+	}
+	for in, want := range map[string]Appearance{"15;0": Dark, "0;15": Light, "15;default;8": Dark, "0;7": Light} {
+		if a, ok := colorFgBg(in); a != want || !ok { t.Errorf("colorFgBg(%q) = %v, %v; want %v", in, a, ok, want) }
+	}
+	for _, in := range []string{"", "15", "15;x", "0;16"} {
+		if _, ok := colorFgBg(in); ok { t.Errorf("colorFgBg(%q) accepted", in) }
+	}
+}
+
+// input turns strings into rune keys and passes other messages through.
+func input(msgs ...any) []tea.Msg {
+	var out []tea.Msg
+	for _, m := range msgs {
+		switch m := m.(type) {
+		case string: out = append(out, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(m)})
+		case tea.Msg: out = append(out, m)
+		}
+	}
+	return out
+}
+
+var (
+	altOpen = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]"), Alt: true}
+	bel     = tea.KeyMsg{Type: tea.KeyCtrlG}
+	st      = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(`\`), Alt: true}
+)
+
+func TestBackgroundReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  Appearance
+		msgs     []tea.Msg
+		consumed string // per message: y or n
+		want     []Appearance
+	}{
+		{"dark reply", Light, input(altOpen, "11;rgb:0000/0000/0000", bel), "yyy", []Appearance{Dark}},
+		{"split body, ST", Light, input(altOpen, "1", "1;rgb:1010/", "1010/1010", st), "yyyyy", []Appearance{Dark}},
+		{"no change", Dark, input(altOpen, "11;rgb:0000/0000/0000", bel), "yyy", nil},
+		{"back and forth", Light, input(altOpen, "11;rgb:0/0/0", bel, altOpen, "11;rgb:f/f/f", bel), "yyyyyy", []Appearance{Dark, Light}},
+		{"keys around a reply", Light, input("q", altOpen, "11;rgb:0/0/0", bel, "w"), "nyyyn", []Appearance{Dark}},
+		{"alt+] then typing", Light, input(altOpen, "hello", bel), "ynn", nil},
+		{"other messages", Light, []tea.Msg{tea.WindowSizeMsg{}, bel}, "nn", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := BackgroundReader{Current: tt.current}
+			var got []Appearance
+			consumed := ""
+			for _, m := range tt.msgs {
+				cmd, ok := r.Update(m)
+				consumed += map[bool]string{true: "y", false: "n"}[ok]
+				if cmd != nil { got = append(got, cmd().(AppearanceMsg).Appearance) }
+			}
+			if consumed != tt.consumed { t.Errorf("consumed %s, want %s", consumed, tt.consumed) }
+			if strings.Join(names(got), ",") != strings.Join(names(tt.want), ",") { t.Errorf("reported %v, want %v", got, tt.want) }
+		})
+	}
+}
+
+func names(as []Appearance) []string {
+	var out []string
+	for _, a := range as { out = append(out, a.String()) }
+	return out
+}
+
+// readerModel forwards Bubble Tea's input to a BackgroundReader and keeps
+// the keys that weren't part of a reply.
+type readerModel struct {
+	bg   BackgroundReader
+	keys []string
+	got  []Appearance
+}
+
+func (m readerModel) Init() tea.Cmd { return nil }
+
+func (m readerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if cmd, ok := m.bg.Update(msg); ok { return m, cmd }
+		m.keys = append(m.keys, msg.String())
+	case AppearanceMsg:
+		m.got = append(m.got, msg.Appearance)
+	}
+	if len(m.got) == 1 && len(m.keys) == 2 { return m, tea.Quit }
+	return m, nil
+}
+
+func (m readerModel) View() string { return "" }
+
+func TestBackgroundReaderInProgram(t *testing.T) {
+	in := strings.NewReader("x\x1b]11;rgb:2424/2424/2323\x07y")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := tea.NewProgram(readerModel{}, tea.WithInput(in), tea.WithOutput(&strings.Builder{}), tea.WithoutRenderer(), tea.WithContext(ctx), tea.WithoutSignals())
+	final, err := p.Run()
+	if err != nil { t.Fatal(err) }
+	m := final.(readerModel)
+	if strings.Join(m.keys, " ") != "x y" || len(m.got) != 1 || m.got[0] != Dark { t.Errorf("keys %q, appearances %v; want x y and dark", m.keys, m.got) }
+}
+
+// syncBuffer is a strings.Builder safe to write from Watch's goroutine.
+type syncBuffer struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) { s.mu.Lock(); defer s.mu.Unlock(); return s.b.Write(p) }
+func (s *syncBuffer) String() string             { s.mu.Lock(); defer s.mu.Unlock(); return s.b.String() }
+
+func TestWatch(t *testing.T) {
+	var out syncBuffer
+	d := Detector{Output: &out, Getenv: func(string) string { return "" }}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan tea.Msg)
+	go func() { done <- d.Watch(ctx, time.Millisecond)() }()
+	deadline := time.Now().Add(5 * time.Second)
+	for strings.Count(out.String(), "\x1b]11;?\x07") < 3 && time.Now().Before(deadline) { time.Sleep(time.Millisecond) }
+	cancel()
+	select {
+	case msg := <-done:
+		if msg != nil { t.Errorf("Watch returned %v", msg) }
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch kept running after cancel")
+	}
+	if got := out.String(); strings.ReplaceAll(got, "\x1b]11;?\x07", "") != "" || got == "" { t.Errorf("Watch wrote %q, want only OSC 11 queries", got) }
+
+	d.Getenv = func(k string) string { return map[string]string{EnvAppearance: "dark"}[k] }
+	if d.Watch(context.Background(), 0) != nil { t.Error("Watch queries despite $STRAWBERRY_APPEARANCE") }
+}
@@ -1,8 +1,13 @@
 package theme
 
 import (
+	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
 )
 
 // StyleSpec is a neutral, serializable style delta produced by class strings.
@@ -11,59 +16,317 @@ type StyleSpec struct {
 	FGHex, BGHex, BorderHex       string
 	FGToken, BGToken, BorderToken string
 
-	Bold, Underline *bool
+	Bold, Underline                            *bool
+	Italic, Dim, Strikethrough, Reverse, Blink *bool
+
+	// Underline refinements: style "single"|"double"|"curly"|"dotted"|"dashed",
+	// color as token or hex (either implies Underline)
+	UnderlineStyle               string
+	UnderlineHex, UnderlineToken string
 
-	// Padding (cells)
-	P, Px, Py, Pt, Pr, Pb, Pl *int
+	// Spacing: raw cells ("2") or a Tokens.Space key ("md"); "" = unset
+	P, Px, Py, Pt, Pr, Pb, Pl string
+	M, Mx, My, Mt, Mr, Mb, Ml string
+	Gap                       string
+
+	// Flex: Direction "row"|"col"; Items "start"|"center"|"end"|"stretch";
+	// Justify also "between"|"around"|"evenly". Basis is spacing like Gap.
+	Direction, Items, Justify string
+	Grow, Shrink              *int
+	Basis                     string
 
 	// Corners
 	RadiusKey string // "none"|"sm"|"md"|"lg"
 	Radius    *int   // explicit override
+
+	// Sizing (cells); WPct/HPct are percentages of the parent ("w-full", "w-1/2")
+	W, H, MinW, MaxW, MinH, MaxH *int
+	WPct, HPct                   *int
+	Aspect                       *float64 // width÷height in cells ("aspect-2/1")
+
+	// Variants are deltas for prefixed utilities ("focus:bg-pink-60",
+	// "md:px-4"), one per distinct condition, in first-use order. The
+	// resolvers ignore them; flatten with Theme.For first.
+	Variants []Variant
 }
 
-// ParseClass converts Tailwind-like utilities into a StyleSpec.
+// ParseClass converts Tailwind-like utilities into a StyleSpec. Utilities
+// may carry variant prefixes (see Variant). Unknown or malformed utilities
+// are skipped (see ParseClassStrict).
 func ParseClass(s string) StyleSpec {
+	spec, _ := parseClass(s, nil)
+	return spec
+}
+
+// ClassError is a problem with one utility of a class string; Pos and End
+// are its byte offsets in the string.
+type ClassError struct {
+	Pos, End int
+	Class    string // the utility
+	Msg      string
+}
+
+// Error reads "col <n>: <utility>: <problem>" (1-based column).
+func (e ClassError) Error() string { return fmt.Sprintf("col %d: %q: %s", e.Pos+1, e.Class, e.Msg) }
+
+// ParseClassStrict parses s like ParseClass and also reports unknown
+// utilities and variant prefixes, bad numbers, invalid hex colors,
+// color/spacing/radius/breakpoint names missing from tokens, and utilities that override an earlier one on the
+// same property ("p-2 p-4"; "p-2 px-4" refines and is fine).
+func ParseClassStrict(s string, tokens Tokens) (StyleSpec, []ClassError) {
+	return parseClass(s, &tokens)
+}
+
+func parseClass(s string, tokens *Tokens) (StyleSpec, []ClassError) {
 	var spec StyleSpec
-	for _, tok := range strings.Fields(s) {
+	var errs []ClassError
+	p := classParser{tokens: tokens}
+	seen := map[string]string{} // property → utility that set it
+	for _, f := range fields(s) {
+		p.msg = ""
+		dst, cond, util := &spec, "", f.text
+		if i := strings.LastIndexByte(f.text, ':'); i >= 0 {
+			dst, cond = p.variant(&spec, f.text[:i])
+			util = f.text[i+1:]
+		}
+		prop := ""
+		if dst != nil { prop = p.apply(dst, util) }
+		if tokens == nil { continue }
 		switch {
-		case strings.HasPrefix(tok, "bg-"):
-			v := tok[3:]; setColor(&spec.BGToken, &spec.BGHex, v)
-		case strings.HasPrefix(tok, "fg-"):
-			v := tok[3:]; setColor(&spec.FGToken, &spec.FGHex, v)
-		case strings.HasPrefix(tok, "border-"):
-			v := tok[7:]; setColor(&spec.BorderToken, &spec.BorderHex, v)
-
-		case tok == "bold":
-			b := true; spec.Bold = &b
-		case tok == "underline":
-			u := true; spec.Underline = &u
-
-		// padding
-		case strings.HasPrefix(tok, "px-"):
-			n := atoi(tok[3:]); spec.Px = &n
-		case strings.HasPrefix(tok, "py-"):
-			n := atoi(tok[3:]); spec.Py = &n
-		case strings.HasPrefix(tok, "pt-"):
-			n := atoi(tok[3:]); spec.Pt = &n
-		case strings.HasPrefix(tok, "pr-"):
-			n := atoi(tok[3:]); spec.Pr = &n
-		case strings.HasPrefix(tok, "pb-"):
-			n := atoi(tok[3:]); spec.Pb = &n
-		case strings.HasPrefix(tok, "pl-"):
-			n := atoi(tok[3:]); spec.Pl = &n
-		case strings.HasPrefix(tok, "p-"):
-			n := atoi(tok[2:]); spec.P = &n
-
-		// radius
-		case strings.HasPrefix(tok, "rounded"):
-			parts := strings.SplitN(tok, "-", 2)
-			if len(parts) == 2 { spec.RadiusKey = parts[1] } else { spec.RadiusKey = "md" }
+		case dst == nil:
+		case prop == "":
+			p.msg = "unknown utility"
+			if c := suggestClass(util); c != "" { p.msg += fmt.Sprintf(" (did you mean %q?)", f.text[:len(f.text)-len(util)]+c) }
+		case p.msg == "" && seen[cond+prop] != "":
+			p.msg = fmt.Sprintf("overrides %q", seen[cond+prop])
 		}
+		if prop != "" { seen[cond+prop] = f.text }
+		if p.msg != "" { errs = append(errs, ClassError{Pos: f.pos, End: f.pos + len(f.text), Class: f.text, Msg: p.msg}) }
 	}
-	return spec
+	return spec, errs
+}
+
+// classParser applies utilities to a StyleSpec, noting the first problem
+// with the current one in msg. Token names are only checked when tokens is
+// set.
+type classParser struct {
+	tokens *Tokens
+	msg    string
+}
+
+func (p *classParser) failf(format string, args ...any) {
+	if p.msg == "" { p.msg = fmt.Sprintf(format, args...) }
+}
+
+// apply sets the fields tok describes and returns the property it sets, or
+// "" for an unknown utility.
+func (p *classParser) apply(spec *StyleSpec, tok string) string {
+	flag := func(dst **bool) { b := true; *dst = &b }
+	switch {
+	case strings.HasPrefix(tok, "bg-"):
+		p.color(&spec.BGToken, &spec.BGHex, tok[3:]); return "bg"
+	case strings.HasPrefix(tok, "fg-"):
+		p.color(&spec.FGToken, &spec.FGHex, tok[3:]); return "fg"
+	case strings.HasPrefix(tok, "border-"):
+		p.color(&spec.BorderToken, &spec.BorderHex, tok[7:]); return "border"
+
+	case tok == "bold":
+		flag(&spec.Bold); return tok
+	case tok == "underline":
+		flag(&spec.Underline); return tok
+	case strings.HasPrefix(tok, "underline-"):
+		v := tok[10:]; flag(&spec.Underline)
+		if underlineStyles[v] { spec.UnderlineStyle = v; return "underline-style" }
+		p.color(&spec.UnderlineToken, &spec.UnderlineHex, v); return "underline-color"
+	case tok == "italic":
+		flag(&spec.Italic); return tok
+	case tok == "dim":
+		flag(&spec.Dim); return tok
+	case tok == "line-through":
+		flag(&spec.Strikethrough); return tok
+	case tok == "reverse":
+		flag(&spec.Reverse); return tok
+	case tok == "blink":
+		flag(&spec.Blink); return tok
+	}
+
+	// spacing
+	for _, u := range spacingUtilities {
+		if v, ok := strings.CutPrefix(tok, u.prefix); ok { *u.field(spec) = p.space(v); return u.prefix[:len(u.prefix)-1] }
+	}
+
+	switch {
+	// flex
+	case tok == "flex-row": spec.Direction = "row"; return "direction"
+	case tok == "flex-col": spec.Direction = "col"; return "direction"
+	case strings.HasPrefix(tok, "items-"):
+		if _, ok := alignOf[tok[6:]]; ok { spec.Items = tok[6:] } else { p.failf("unknown alignment %q (want start|center|end|stretch)", tok[6:]) }
+		return "items"
+	case strings.HasPrefix(tok, "justify-"):
+		if _, ok := justifyOf[tok[8:]]; ok { spec.Justify = tok[8:] } else { p.failf("unknown justify %q (want start|center|end|between|around|evenly)", tok[8:]) }
+		return "justify"
+	case tok == "grow":
+		n := 1; spec.Grow = &n; return "grow"
+	case strings.HasPrefix(tok, "grow-"):
+		n := p.num(tok[5:]); spec.Grow = &n; return "grow"
+	case tok == "shrink":
+		n := 1; spec.Shrink = &n; return "shrink"
+	case strings.HasPrefix(tok, "shrink-"):
+		n := p.num(tok[7:]); spec.Shrink = &n; return "shrink"
+
+	// sizing
+	case strings.HasPrefix(tok, "w-"):
+		spec.W, spec.WPct = p.size(tok[2:]); return "w"
+	case strings.HasPrefix(tok, "h-"):
+		spec.H, spec.HPct = p.size(tok[2:]); return "h"
+	case strings.HasPrefix(tok, "min-w-"):
+		n := p.num(tok[6:]); spec.MinW = &n; return "min-w"
+	case strings.HasPrefix(tok, "max-w-"):
+		n := p.num(tok[6:]); spec.MaxW = &n; return "max-w"
+	case strings.HasPrefix(tok, "min-h-"):
+		n := p.num(tok[6:]); spec.MinH = &n; return "min-h"
+	case strings.HasPrefix(tok, "max-h-"):
+		n := p.num(tok[6:]); spec.MaxH = &n; return "max-h"
+	case strings.HasPrefix(tok, "aspect-"):
+		if a, b, ok := fraction(tok[7:]); ok && a > 0 { r := float64(a) / float64(b); spec.Aspect = &r } else { p.failf("bad ratio %q (want <a>/<b>)", tok[7:]) }
+		return "aspect"
+
+	// radius
+	case tok == "rounded":
+		spec.RadiusKey = "md"; return "radius"
+	case strings.HasPrefix(tok, "rounded-"):
+		spec.RadiusKey = tok[8:]
+		if p.tokens != nil {
+			if _, ok := p.tokens.Radius[spec.RadiusKey]; !ok { p.failf("unknown radius %q%s", spec.RadiusKey, didYouMean(spec.RadiusKey, keys(p.tokens.Radius))) }
+		}
+		return "radius"
+	}
+	return ""
+}
+
+// spacingUtilities map spacing prefixes to their StyleSpec fields; longer
+// prefixes come first so "px-" wins over "p-".
+var spacingUtilities = []struct {
+	prefix string
+	field  func(*StyleSpec) *string
+}{
+	{"px-", func(s *StyleSpec) *string { return &s.Px }}, {"py-", func(s *StyleSpec) *string { return &s.Py }},
+	{"pt-", func(s *StyleSpec) *string { return &s.Pt }}, {"pr-", func(s *StyleSpec) *string { return &s.Pr }},
+	{"pb-", func(s *StyleSpec) *string { return &s.Pb }}, {"pl-", func(s *StyleSpec) *string { return &s.Pl }},
+	{"p-", func(s *StyleSpec) *string { return &s.P }},
+	{"mx-", func(s *StyleSpec) *string { return &s.Mx }}, {"my-", func(s *StyleSpec) *string { return &s.My }},
+	{"mt-", func(s *StyleSpec) *string { return &s.Mt }}, {"mr-", func(s *StyleSpec) *string { return &s.Mr }},
+	{"mb-", func(s *StyleSpec) *string { return &s.Mb }}, {"ml-", func(s *StyleSpec) *string { return &s.Ml }},
+	{"m-", func(s *StyleSpec) *string { return &s.M }},
+	{"gap-", func(s *StyleSpec) *string { return &s.Gap }},
+	{"basis-", func(s *StyleSpec) *string { return &s.Basis }},
 }
 
+func (p *classParser) color(tok, hex *string, v string) {
+	setColor(tok, hex, v)
+	switch {
+	case strings.HasPrefix(v, "#"):
+		if !isHex(v) { p.failf("invalid hex color %q (want #RRGGBB)", v) }
+	case p.tokens != nil && !p.tokens.HasColor(v):
+		p.failf("unknown color token %q%s", v, didYouMean(v, p.tokens.ColorNames()))
+	}
+}
+
+func (p *classParser) space(v string) string {
+	if p.tokens == nil { return v }
+	if _, ok := p.tokens.Space[v]; ok { return v }
+	if n, err := strconv.Atoi(v); err != nil || n < 0 {
+		p.failf("bad spacing %q (want cells or one of %s)%s", v, strings.Join(keys(p.tokens.Space), "|"), didYouMean(v, keys(p.tokens.Space)))
+	}
+	return v
+}
+
+func (p *classParser) num(v string) int {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 { p.failf("bad number %q", v); return 0 }
+	return n
+}
+
+// size reads "<n>" as cells, "full" as 100% and "<a>/<b>" as a percentage.
+func (p *classParser) size(v string) (cells, pct *int) {
+	if v == "full" { n := 100; return nil, &n }
+	if a, b, ok := fraction(v); ok { n := a * 100 / b; return nil, &n }
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 { p.failf("bad size %q (want <n>, full or <a>/<b>)", v); n = 0 }
+	return &n, nil
+}
+
+var underlineStyles = map[string]bool{"single": true, "double": true, "curly": true, "dotted": true, "dashed": true}
+
 func setColor(tok *string, hex *string, v string) {
 	if strings.HasPrefix(v, "#") { *hex = v } else { *tok = v }
 }
-func atoi(s string) int { n, _ := strconv.Atoi(s); return n }
+
+func isHex(v string) bool {
+	if len(v) != 7 || v[0] != '#' { return false }
+	for _, c := range v[1:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) { return false }
+	}
+	return true
+}
+
+// fraction parses "<a>/<b>" with a >= 0 and b > 0.
+func fraction(v string) (a, b int, ok bool) {
+	num, den, found := strings.Cut(v, "/")
+	if !found { return 0, 0, false }
+	a, err1 := strconv.Atoi(num)
+	b, err2 := strconv.Atoi(den)
+	return a, b, err1 == nil && err2 == nil && a >= 0 && b > 0
+}
+
+type field struct {
+	pos  int
+	text string
+}
+
+// fields splits s like strings.Fields, keeping each field's byte offset.
+func fields(s string) []field {
+	var out []field
+	start := -1
+	for i, r := range s {
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			out, start = append(out, field{start, s[start:i]}), -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 { out = append(out, field{start, s[start:]}) }
+	return out
+}
+
+// Every utility name and prefix, for "did you mean" hints.
+var (
+	classNames    = []string{"bold", "underline", "italic", "dim", "line-through", "reverse", "blink", "flex-row", "flex-col", "grow", "shrink", "rounded"}
+	classPrefixes = []string{"bg-", "fg-", "border-", "underline-", "items-", "justify-", "grow-", "shrink-", "w-", "h-", "min-w-", "max-w-", "min-h-", "max-h-", "aspect-", "rounded-"}
+)
+
+// suggestClass returns the known utility closest to tok, keeping tok's value
+// ("bgg-pink-50" → "bg-pink-50").
+func suggestClass(tok string) string {
+	cands := append([]string(nil), classNames...)
+	prefixes := classPrefixes
+	for _, u := range spacingUtilities { prefixes = append(prefixes, u.prefix) }
+	for i := 0; i < len(tok); i++ {
+		if tok[i] != '-' { continue }
+		for _, pre := range prefixes { cands = append(cands, pre+tok[i+1:]) }
+	}
+	return renderer.Closest(tok, cands)
+}
+
+func didYouMean(v string, cands []string) string {
+	if c := renderer.Closest(v, cands); c != "" { return fmt.Sprintf(" (did you mean %q?)", c) }
+	return ""
+}
+
+func keys[V any](m map[string]V) []string {
+	out := make([]string, 0, len(m))
+	for k := range m { out = append(out, k) }
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,147 @@
+package theme
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+)
+
+func TestParseClassStrictErrors(t *testing.T) {
+	tests := []struct {
+		class string
+		want  []string // ClassError.Error() of each problem
+		pos   []int    // and its Pos, End
+	}{
+		{"p-2 bg-primary hover:bold md:p-2", nil, nil},
+		{"p-2 px-4", nil, nil},
+		{"hover:p-1 p-1", nil, nil},
+		{"  bgg-primary bold", []string{`col 3: "bgg-primary": unknown utility (did you mean "bg-primary"?)`}, []int{2, 13}},
+		{"p-2 p-4", []string{`col 5: "p-4": overrides "p-2"`}, []int{4, 7}},
+		{"p-1  hover:p-1 hover:p-2", []string{`col 16: "hover:p-2": overrides "hover:p-1"`}, []int{15, 24}},
+		{"bg-nope", []string{`col 1: "bg-nope": unknown color token "nope"`}, []int{0, 7}},
+		{"bold fg-#12", []string{`col 6: "fg-#12": invalid hex color "#12" (want #RRGGBB)`}, []int{5, 11}},
+		{"w-abc", []string{`col 1: "w-abc": bad size "abc" (want <n>, full or <a>/<b>)`}, []int{0, 5}},
+		{"aspect-3", []string{`col 1: "aspect-3": bad ratio "3" (want <a>/<b>)`}, []int{0, 8}},
+		{"wat:bold", []string{`col 1: "wat:bold": unknown variant "wat"`}, []int{0, 8}},
+		{"rounded-huge", []string{`col 1: "rounded-huge": unknown radius "huge"`}, []int{0, 12}},
+		{"items-middle", []string{`col 1: "items-middle": unknown alignment "middle" (want start|center|end|stretch)`}, []int{0, 12}},
+		{"p-x", []string{`col 1: "p-x": bad spacing "x" (want cells or one of lg|md|sm|xl|xs) (did you mean "xl"?)`}, []int{0, 3}},
+		{"xxl:p-2 bg-nope", []string{`col 1: "xxl:p-2": unknown variant "xxl"`, `col 9: "bg-nope": unknown color token "nope"`}, []int{0, 7, 8, 15}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.class, func(t *testing.T) {
+			_, errs := ParseClassStrict(tt.class, DefaultTokens())
+			if len(errs) != len(tt.want) { t.Fatalf("got %d errors %v, want %d", len(errs), errs, len(tt.want)) }
+			for i, e := range errs {
+				if e.Error() != tt.want[i] { t.Errorf("error %d = %s, want %s", i, e, tt.want[i]) }
+				if e.Pos != tt.pos[2*i] || e.End != tt.pos[2*i+1] { t.Errorf("error %d at [%d,%d), want [%d,%d)", i, e.Pos, e.End, tt.pos[2*i], tt.pos[2*i+1]) }
+				if got := tt.class[e.Pos:e.End]; got != e.Class { t.Errorf("error %d: class[%d:%d] = %q, want %q", i, e.Pos, e.End, got, e.Class) }
+			}
+		})
+	}
+}
+
+func TestParseClassStrictMatchesParseClass(t *testing.T) {
+	const class = "p-2 p-4 bgg-primary fg-text hover:bold"
+	spec, _ := ParseClassStrict(class, DefaultTokens())
+	if want := ParseClass(class); !reflect.DeepEqual(spec, want) { t.Errorf("strict spec %+v, want %+v", spec, want) }
+}
+
+func TestTextStyleClasses(t *testing.T) {
+	danger := toTermColor(DefaultTokens().Color("danger"))
+	tests := []struct {
+		class string
+		want  ui.Attr
+	}{
+		{"italic dim", ui.Attr{Italic: true, Dim: true}},
+		{"line-through", ui.Attr{Strikethrough: true}},
+		{"reverse blink", ui.Attr{Reverse: true, Blink: true}},
+		{"underline", ui.Attr{Underline: true}},
+		{"underline-single", ui.Attr{Underline: true}},
+		{"underline-double", ui.Attr{Underline: true, UnderlineStyle: ui.UnderlineDouble}},
+		{"underline-curly", ui.Attr{Underline: true, UnderlineStyle: ui.UnderlineCurly}},
+		{"underline-dotted", ui.Attr{Underline: true, UnderlineStyle: ui.UnderlineDotted}},
+		{"underline-dashed", ui.Attr{Underline: true, UnderlineStyle: ui.UnderlineDashed}},
+		{"underline-#FF0000", ui.Attr{Underline: true, UnderlineColor: toTermColor("#FF0000")}},
+		{"underline-danger", ui.Attr{Underline: true, UnderlineColor: danger}},
+		{"underline-curly underline-danger", ui.Attr{Underline: true, UnderlineStyle: ui.UnderlineCurly, UnderlineColor: danger}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.class, func(t *testing.T) {
+			spec, errs := ParseClassStrict(tt.class, DefaultTokens())
+			if len(errs) != 0 { t.Fatalf("errors: %v", errs) }
+			if got := Default().ResolveTUI(spec).Attr; got != tt.want { t.Errorf("attr = %+v, want %+v", got, tt.want) }
+		})
+	}
+
+	for class, want := range map[string]string{
+		"italics":        `col 1: "italics": unknown utility (did you mean "italic"?)`,
+		"underline-wavy": `col 1: "underline-wavy": unknown color token "wavy"`,
+		"strikethrough":  `col 1: "strikethrough": unknown utility`,
+	} {
+		_, errs := ParseClassStrict(class, DefaultTokens())
+		if len(errs) != 1 || errs[0].Error() != want { t.Errorf("%s: errors %v, want %s", class, errs, want) }
+	}
+}
+
+func TestMarginClasses(t *testing.T) {
+	tests := []struct {
+		class string
+		want  ui.Padding
+	}{
+		{"m-1", ui.Padding{T: 1, R: 1, B: 1, L: 1}},
+		{"mx-2", ui.Padding{R: 2, L: 2}},
+		{"my-sm", ui.Padding{T: 2, B: 2}},
+		{"mt-1 mr-2 mb-3 ml-4", ui.Padding{T: 1, R: 2, B: 3, L: 4}},
+		{"m-1 mt-3", ui.Padding{T: 3, R: 1, B: 1, L: 1}},
+		{"mx-1 ml-md", ui.Padding{R: 1, L: 4}},
+		{"ml-md mx-1", ui.Padding{R: 1, L: 4}}, // a side beats an axis in any order
+		{"p-2", ui.Padding{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.class, func(t *testing.T) {
+			spec, errs := ParseClassStrict(tt.class, DefaultTokens())
+			if len(errs) != 0 { t.Fatalf("errors: %v", errs) }
+			r := Default().ResolveTUI(spec)
+			if r.Margin != tt.want { t.Errorf("margin = %+v, want %+v", r.Margin, tt.want) }
+			n := ui.Box("n", r.Options()...)
+			if got, ok := ui.PropMargin.Get(n); ok != (tt.want != ui.Padding{}) || got != tt.want { t.Errorf("margin prop = %+v (set %v), want %+v", got, ok, tt.want) }
+		})
+	}
+	if _, errs := ParseClassStrict("mx-huge", DefaultTokens()); len(errs) != 1 { t.Errorf("mx-huge: errors %v, want one bad spacing", errs) }
+}
+
+func TestSizeClasses(t *testing.T) {
+	tests := []struct {
+		class         string
+		w, wPct, hPct int
+		aspect        float64
+	}{
+		{"w-7", 7, 0, 0, 0},
+		{"w-full", 0, 100, 0, 0},
+		{"w-1/2", 0, 50, 0, 0},
+		{"h-1/3", 0, 0, 33, 0},
+		{"w-8 w-1/4", 0, 25, 0, 0}, // the later form of a side wins
+		{"w-1/4 w-8", 8, 0, 0, 0},
+		{"aspect-2/1", 0, 0, 0, 2},
+		{"aspect-16/9 w-full", 0, 100, 0, 16.0 / 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.class, func(t *testing.T) {
+			spec := ParseClass(tt.class)
+			r := Default().ResolveTUI(spec)
+			w := 0
+			if r.W != nil { w = *r.W }
+			if w != tt.w || r.WPercent != tt.wPct || r.HPercent != tt.hPct || r.Aspect != tt.aspect {
+				t.Errorf("w %d, w%% %d, h%% %d, aspect %v; want %d, %d, %d, %v", w, r.WPercent, r.HPercent, r.Aspect, tt.w, tt.wPct, tt.hPct, tt.aspect)
+			}
+			n := ui.Box("n", r.Options()...)
+			if got, _ := ui.PropWPercent.Get(n); got != tt.wPct { t.Errorf("w-pct prop = %d, want %d", got, tt.wPct) }
+			if got, _ := ui.PropAspect.Get(n); got != tt.aspect { t.Errorf("aspect prop = %v, want %v", got, tt.aspect) }
+		})
+	}
+	for _, class := range []string{"w-1/0", "aspect-0/1", "h-half"} {
+		if _, errs := ParseClassStrict(class, DefaultTokens()); len(errs) != 1 { t.Errorf("%s: errors %v, want one", class, errs) }
+	}
+}
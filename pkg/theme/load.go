@@ -0,0 +1,161 @@
+package theme
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// themeFile is the on-disk form of a theme. Colors are "#RRGGBB" or the
+// name of another color, so derived tokens ("primary: pink-60") follow
+// their base; roles and borders are colors too. Motion is in milliseconds.
+// Scales map a name to a seed color and add its Scale to the palette;
+// colors given explicitly win over generated steps. Sections left out keep
+// DefaultTokens' values, and map sections add to the defaults' entries
+// (see Merge).
+type themeFile struct {
+	Name        string            `json:"name" yaml:"name" toml:"name"`
+	Colors      map[string]string `json:"colors" yaml:"colors" toml:"colors"`
+	Scales      map[string]string `json:"scales" yaml:"scales" toml:"scales"`
+	Spacing     map[string]int    `json:"spacing" yaml:"spacing" toml:"spacing"`
+	Radius      map[string]int    `json:"radius" yaml:"radius" toml:"radius"`
+	Breakpoints map[string]int    `json:"breakpoints" yaml:"breakpoints" toml:"breakpoints"`
+	Roles       struct {
+		Bg        string `json:"bg" yaml:"bg" toml:"bg"`
+		Surface   string `json:"surface" yaml:"surface" toml:"surface"`
+		Text      string `json:"text" yaml:"text" toml:"text"`
+		Primary   string `json:"primary" yaml:"primary" toml:"primary"`
+		PrimaryFg string `json:"primary-fg" yaml:"primary-fg" toml:"primary-fg"`
+	} `json:"roles" yaml:"roles" toml:"roles"`
+	Border struct {
+		Normal  string `json:"normal" yaml:"normal" toml:"normal"`
+		Focused string `json:"focused" yaml:"focused" toml:"focused"`
+	} `json:"border" yaml:"border" toml:"border"`
+	Motion struct {
+		Fast   *int `json:"fast" yaml:"fast" toml:"fast"`
+		Normal *int `json:"normal" yaml:"normal" toml:"normal"`
+		Slow   *int `json:"slow" yaml:"slow" toml:"slow"`
+	} `json:"motion" yaml:"motion" toml:"motion"`
+
+	// Variants are partial themes layered on top by Theme.Variant
+	// ("dark", "high-contrast"); a nested variants section is ignored.
+	Variants map[string]themeFile `json:"variants" yaml:"variants" toml:"variants"`
+}
+
+// LoadFile reads a theme from a .yaml/.yml, .json or .toml file. A theme
+// without a name is named after the file.
+func LoadFile(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil { return Theme{}, fmt.Errorf("theme: %w", err) }
+	ext := filepath.Ext(path)
+	th, err := load(data, strings.TrimPrefix(ext, "."), "theme: "+path)
+	if err != nil { return Theme{}, err }
+	if th.Name == "" { th.Name = strings.TrimSuffix(filepath.Base(path), ext) }
+	return th, nil
+}
+
+// Load parses a theme in format "yaml", "json" or "toml". Every problem is
+// reported (joined): unknown keys, invalid hex colors, references to missing
+// colors or reference cycles, and negative sizes or durations.
+func Load(data []byte, format string) (Theme, error) { return load(data, format, "theme") }
+
+func load(data []byte, format, where string) (Theme, error) {
+	var f themeFile
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&f); err != nil && err != io.EOF { return Theme{}, fmt.Errorf("%s: %w", where, err) }
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&f); err != nil { return Theme{}, fmt.Errorf("%s: %w", where, err) }
+	case "toml":
+		md, err := toml.Decode(string(data), &f)
+		if err != nil { return Theme{}, fmt.Errorf("%s: %w", where, err) }
+		if und := md.Undecoded(); len(und) > 0 { return Theme{}, fmt.Errorf("%s: unknown key %q", where, und[0].String()) }
+	default:
+		return Theme{}, fmt.Errorf("%s: unknown format %q (want yaml, json or toml)", where, format)
+	}
+	return f.theme(where)
+}
+
+// theme resolves f over the default theme.
+func (f themeFile) theme(where string) (Theme, error) {
+	var errs []error
+	bad := func(format string, args ...any) { errs = append(errs, fmt.Errorf(where+": "+format, args...)) }
+	wrap := func(prefix string, es []error) {
+		for _, e := range es { errs = append(errs, fmt.Errorf("%s: %s%w", where, prefix, e)) }
+	}
+
+	th, es := merge(Default(), f.partial("", bad))
+	wrap("", es)
+	th.Name = f.Name
+	// Durations are set here rather than merged so a file can use 0.
+	ms := func(dst *time.Duration, v *int) { if v != nil && *v >= 0 { *dst = time.Duration(*v) * time.Millisecond } }
+	ms(&th.Tokens.Motion.Fast, f.Motion.Fast)
+	ms(&th.Tokens.Motion.Normal, f.Motion.Normal)
+	ms(&th.Tokens.Motion.Slow, f.Motion.Slow)
+
+	// Variants stay partial (Theme.Variant merges them); check they resolve.
+	for _, name := range keys(f.Variants) {
+		prefix := "variants." + name + "."
+		v := f.Variants[name].partial(prefix, bad)
+		_, es := merge(th, v)
+		wrap(prefix, es)
+		th.Variants[name] = v
+	}
+
+	if len(errs) > 0 { return Theme{}, errors.Join(errs...) }
+	return th, nil
+}
+
+// partial converts f to the overrides it describes, leaving colors
+// unresolved and reporting negative sizes and durations.
+func (f themeFile) partial(prefix string, bad func(string, ...any)) Theme {
+	th := Theme{Name: f.Name}
+	t := &th.Tokens
+	t.Palette = f.Colors
+	if len(f.Scales) > 0 {
+		t.Palette = map[string]string{}
+		known := union(DefaultTokens().Palette, f.Colors)
+		for _, name := range keys(f.Scales) {
+			seed, err := resolveColor(known, f.Scales[name])
+			if err != nil { bad("%sscales.%s: %v", prefix, name, err); continue }
+			s, _ := Scale(name, seed)
+			maps.Copy(t.Palette, s)
+		}
+		maps.Copy(t.Palette, f.Colors)
+	}
+	t.Colors.Bg, t.Colors.Surface, t.Colors.Text = f.Roles.Bg, f.Roles.Surface, f.Roles.Text
+	t.Colors.Primary, t.Colors.PrimaryFg = f.Roles.Primary, f.Roles.PrimaryFg
+	t.Border.Normal, t.Border.Focused = f.Border.Normal, f.Border.Focused
+
+	sizes := func(section string, src map[string]int) map[string]int {
+		for _, k := range keys(src) {
+			if src[k] < 0 { bad("%s%s.%s: negative value %d", prefix, section, k, src[k]); delete(src, k) }
+		}
+		return src
+	}
+	t.Space = sizes("spacing", f.Spacing)
+	t.Radius = sizes("radius", f.Radius)
+	t.Breakpoints = sizes("breakpoints", f.Breakpoints)
+
+	ms := func(field string, v *int) time.Duration {
+		if v == nil { return 0 }
+		if *v < 0 { bad("%smotion.%s: negative duration %d", prefix, field, *v); return 0 }
+		return time.Duration(*v) * time.Millisecond
+	}
+	t.Motion = Motion{Fast: ms("fast", f.Motion.Fast), Normal: ms("normal", f.Motion.Normal), Slow: ms("slow", f.Motion.Slow)}
+	return th
+}
@@ -0,0 +1,49 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadRejectsUnknownFields(t *testing.T) {
+	tests := []struct {
+		format, data string
+		want         string // in the error
+	}{
+		{"yaml", "name: x\ncolour: red\n", "line 2: field colour not found"},
+		{"yaml", "roles:\n  background: \"#000000\"\n", "line 2: field background not found"},
+		{"yaml", "variants:\n  dark:\n    rolls: {}\n", "line 3: field rolls not found"},
+		{"json", `{"name":"x","colour":"red"}`, `unknown field "colour"`},
+		{"json", `{"roles":{"background":"#000000"}}`, `unknown field "background"`},
+		{"json", `{"variants":{"dark":{"rolls":{}}}}`, `unknown field "rolls"`},
+		{"toml", "name = \"x\"\ncolour = \"red\"\n", `unknown key "colour"`},
+		{"toml", "[roles]\nbackground = \"#000000\"\n", `unknown key "roles.background"`},
+		{"toml", "[variants.dark.rolls]\n", `unknown key "variants.dark.rolls"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.want, func(t *testing.T) {
+			_, err := Load([]byte(tt.data), tt.format)
+			if err == nil { t.Fatal("loaded a theme with an unknown field") }
+			if !strings.HasPrefix(err.Error(), "theme: ") || !strings.Contains(err.Error(), tt.want) { t.Errorf("error %q, want it to mention %q", err, tt.want) }
+		})
+	}
+}
+
+func TestLoadKnownFields(t *testing.T) {
+	files := map[string]string{
+		"yaml": "name: mint\ncolors:\n  mint: \"#3EB489\"\nroles:\n  primary: mint\nvariants:\n  dark:\n    roles:\n      bg: \"#000000\"\n",
+		"json": `{"name":"mint","colors":{"mint":"#3EB489"},"roles":{"primary":"mint"},"variants":{"dark":{"roles":{"bg":"#000000"}}}}`,
+		"toml": "name = \"mint\"\n[colors]\nmint = \"#3EB489\"\n[roles]\nprimary = \"mint\"\n[variants.dark.roles]\nbg = \"#000000\"\n",
+	}
+	for format, data := range files {
+		th, err := Load([]byte(data), format)
+		if err != nil { t.Errorf("%s: %v", format, err); continue }
+		if th.Name != "mint" || th.Tokens.Color("primary") != "#3EB489" || th.Variant("dark").Tokens.Color("bg") != "#000000" {
+			t.Errorf("%s: loaded %q with primary %s, dark bg %s", format, th.Name, th.Tokens.Color("primary"), th.Variant("dark").Tokens.Color("bg"))
+		}
+	}
+}
+
+func TestLoadUnknownFormat(t *testing.T) {
+	if _, err := Load([]byte("name: x"), "ini"); err == nil || err.Error() != `theme: unknown format "ini" (want yaml, json or toml)` { t.Errorf("err = %v", err) }
+}
@@ -0,0 +1,98 @@
+package theme
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Merge layers overrides on base, as in base theme → named variant → app
+// overrides. Non-empty colors and non-zero durations replace base's, map
+// entries (palette, spacing, radius, breakpoints, variants) are added or
+// replaced, and a color may name another color of the merged palette
+// ("primary: red-60"). Borders that followed base's text/primary-fg role
+// keep following it. overrides is usually a partial Theme:
+//
+//	danger := theme.Theme{}
+//	danger.Tokens.Colors.Primary = "#E5484D"
+//	th = theme.Merge(th, danger)
+//
+// Names that don't resolve are kept as given (see Load for validation).
+func Merge(base, overrides Theme) Theme {
+	th, _ := merge(base, overrides)
+	return th
+}
+
+// Variant returns th with its named variant ("dark", "high-contrast")
+// merged on top, or th unchanged when it has no such variant.
+func (th Theme) Variant(name string) Theme {
+	v, ok := th.Variants[name]
+	if !ok { return th }
+	if v.Name == "" { v.Name = th.Name + "-" + name }
+	return Merge(th, v)
+}
+
+// merge is Merge that also reports colors that don't resolve, named by
+// their theme file path ("roles.primary").
+func merge(base, over Theme) (Theme, []error) {
+	var errs []error
+	out := base
+	if over.Name != "" { out.Name = over.Name }
+	t, b, o := &out.Tokens, base.Tokens, over.Tokens
+
+	raw := union(b.Palette, o.Palette)
+	t.Palette = make(map[string]string, len(raw))
+	for _, name := range keys(raw) {
+		hex, err := resolveColor(raw, raw[name])
+		if err != nil { errs = append(errs, fmt.Errorf("colors.%s: %w", name, err)); hex = raw[name] }
+		t.Palette[name] = hex
+	}
+	color := func(dst *string, field, v string) {
+		if v == "" { return }
+		hex, err := resolveColor(raw, v)
+		if err != nil { errs = append(errs, fmt.Errorf("%s: %w", field, err)); hex = v }
+		*dst = hex
+	}
+	color(&t.Colors.Bg, "roles.bg", o.Colors.Bg)
+	color(&t.Colors.Surface, "roles.surface", o.Colors.Surface)
+	color(&t.Colors.Text, "roles.text", o.Colors.Text)
+	color(&t.Colors.Primary, "roles.primary", o.Colors.Primary)
+	color(&t.Colors.PrimaryFg, "roles.primary-fg", o.Colors.PrimaryFg)
+	if b.Border.Normal == b.Colors.Text { t.Border.Normal = t.Colors.Text }
+	if b.Border.Focused == b.Colors.PrimaryFg { t.Border.Focused = t.Colors.PrimaryFg }
+	color(&t.Border.Normal, "border.normal", o.Border.Normal)
+	color(&t.Border.Focused, "border.focused", o.Border.Focused)
+
+	t.Space = union(b.Space, o.Space)
+	t.Radius = union(b.Radius, o.Radius)
+	t.Breakpoints = union(b.Breakpoints, o.Breakpoints)
+	if o.Motion.Fast != 0 { t.Motion.Fast = o.Motion.Fast }
+	if o.Motion.Normal != 0 { t.Motion.Normal = o.Motion.Normal }
+	if o.Motion.Slow != 0 { t.Motion.Slow = o.Motion.Slow }
+	out.Variants = union(base.Variants, over.Variants)
+	return out, errs
+}
+
+// resolveColor follows color names through palette to a #RRGGBB value.
+func resolveColor(palette map[string]string, v string) (string, error) {
+	var seen []string
+	for !strings.HasPrefix(v, "#") {
+		next, ok := palette[v]
+		switch {
+		case !ok: return "", fmt.Errorf("unknown color %q%s", v, didYouMean(v, keys(palette)))
+		case slices.Contains(seen, v): return "", fmt.Errorf("reference cycle through %q", v)
+		}
+		seen, v = append(seen, v), next
+	}
+	if !isHex(v) { return "", fmt.Errorf("invalid hex color %q (want #RRGGBB)", v) }
+	return v, nil
+}
+
+// union returns a new map with b's entries over a's.
+func union[V any](a, b map[string]V) map[string]V {
+	out := make(map[string]V, len(a)+len(b))
+	maps.Copy(out, a)
+	maps.Copy(out, b)
+	return out
+}
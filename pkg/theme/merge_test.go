@@ -0,0 +1,27 @@
+package theme
+
+import "testing"
+
+func TestMergeKeepsDerivedBorders(t *testing.T) {
+	var red, ink Theme
+	red.Tokens.Colors.Primary = "#E5484D"
+	ink.Tokens.Colors.PrimaryFg = "#000000"
+	def := DefaultTokens()
+
+	got := Merge(Default(), red).Tokens
+	if got.Colors.Primary != "#E5484D" { t.Errorf("primary = %s, want #E5484D", got.Colors.Primary) }
+	if got.Border.Focused != def.Border.Focused { t.Errorf("focused border = %s, want %s (it follows primary-fg)", got.Border.Focused, def.Border.Focused) }
+
+	got = Merge(Default(), ink).Tokens
+	if got.Border.Focused != "#000000" { t.Errorf("focused border = %s, want it to follow primary-fg to #000000", got.Border.Focused) }
+	if got.Colors.Primary != def.Colors.Primary { t.Errorf("primary = %s, want the default %s", got.Colors.Primary, def.Colors.Primary) }
+}
+
+func TestDarkVariantKeepsOwnPrimary(t *testing.T) {
+	var red Theme
+	red.Tokens.Colors.Primary = "#E5484D"
+	red.Tokens.Colors.PrimaryFg = "#FFFFFF"
+	got := Merge(Default(), red).Variant("dark").Tokens
+	if got.Colors.Primary != "#E5484D" || got.Colors.PrimaryFg != "#FFFFFF" { t.Errorf("primary %s on %s, want the theme's own #E5484D on #FFFFFF", got.Colors.Primary, got.Colors.PrimaryFg) }
+	if got.Border.Focused != "#F4ACB7" { t.Errorf("focused border = %s, want pink-60", got.Border.Focused) }
+}
@@ -0,0 +1,124 @@
+package theme
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Watcher reloads a theme file while an app runs, so tokens can be tuned
+// live. It polls the file's size and modification time, which works the
+// same on every OS and for editors that save by renaming a new file over
+// the old one.
+type Watcher struct {
+	Path     string
+	Interval time.Duration // poll period (default 500ms)
+}
+
+// NewWatcher returns a Watcher for path; load the first theme yourself.
+func NewWatcher(path string) *Watcher { return &Watcher{Path: path} }
+
+// ReloadMsg carries the theme reloaded from Path, or Err when the file
+// didn't load; keep the current theme then and show Err (see ErrorNode).
+type ReloadMsg struct {
+	Path  string
+	Theme Theme
+	Err   error
+}
+
+// Watch returns a command that waits for the file to change from how it
+// is when Watch is called, reloads it and reports the result, or returns
+// nil once ctx is done. Keep one Watch outstanding and issue it again after
+// each ReloadMsg:
+//
+//	case theme.ReloadMsg:
+//		m.themeErr = msg.Err
+//		if msg.Err == nil {
+//			stale := theme.Changed(m.tree, m.theme, msg.Theme, m.env)
+//			m.theme = msg.Theme
+//			theme.Apply(m.tree, m.theme, m.env)
+//			if inv, ok := m.engine.(ui.Invalidator); ok { inv.Invalidate(stale...) }
+//		}
+//		return m, m.watcher.Watch(m.ctx)
+func (w *Watcher) Watch(ctx context.Context) tea.Cmd {
+	path, every := w.Path, w.Interval
+	if every <= 0 { every = 500 * time.Millisecond }
+	seenMod, seenSize, _ := stat(path)
+	return func() tea.Msg {
+		t := time.NewTicker(every)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done(): return nil
+			case <-t.C:
+			}
+			mod, size, ok := stat(path)
+			if !ok || (mod.Equal(seenMod) && size == seenSize) { continue }
+			// Let a save in progress finish before parsing it: wait until two
+			// stats in a row match, through any gap where the file is missing.
+			for {
+				select {
+				case <-ctx.Done(): return nil
+				case <-time.After(every / 10):
+				}
+				m2, s2, ok := stat(path)
+				if ok && m2.Equal(mod) && s2 == size { break }
+				mod, size = m2, s2
+			}
+			th, err := LoadFile(path)
+			return ReloadMsg{Path: path, Theme: th, Err: err}
+		}
+	}
+}
+
+// stat reports ok=false while the file is missing, e.g. between an
+// editor's delete and rename.
+func stat(path string) (time.Time, int64, bool) {
+	st, err := os.Stat(path)
+	if err != nil { return time.Time{}, 0, false }
+	return st.ModTime(), st.Size(), true
+}
+
+// Changed lists the keys of the nodes in root whose classes resolve
+// differently under next than under prev (see Apply), for
+// ui.Invalidator when the tree is restyled in place. It compares the props
+// Apply would give each node, starting like Apply from the node's own, so
+// it can run before or after Apply restyles the tree.
+func Changed(root ui.Node, prev, next Theme, env Env) []ui.Key {
+	var out []ui.Key
+	if root != nil { changed(root, renderer.RootKey(root), prev, next, env, &out) }
+	return out
+}
+
+func changed(n ui.Node, k ui.Key, prev, next Theme, env Env, out *[]ui.Key) {
+	p := n.Props()
+	if over, ok := PropTheme.Lookup(p); ok { prev, next = Merge(prev, over), Merge(next, over) }
+	base, styled := propBase.Lookup(p)
+	if !styled { base = ownProps(p) }
+	if class, _ := ui.PropClass.Lookup(p); styled || class != "" {
+		if !reflect.DeepEqual(prev.styled(n, class, env, base), next.styled(n, class, env, base)) { *out = append(*out, k) }
+	}
+	kids := n.Children()
+	for i, ck := range renderer.ChildKeys(k, n) { changed(kids[i], ck, prev, next, env, out) }
+}
+
+// ErrorNode shows err in the app, one line per problem in a danger-colored
+// frame, so a broken theme file can be fixed without a restart:
+//
+//	if m.themeErr != nil { kids = append(kids, theme.ErrorNode("theme-error", m.themeErr)) }
+func ErrorNode(id string, err error) ui.Node {
+	red := toTermColor(DefaultTokens().Color("danger"))
+	lines := []ui.Node{ui.Text("title", "theme not reloaded", ui.Attr{FG: red, Bold: true})}
+	for i, line := range strings.Split(err.Error(), "\n") {
+		lines = append(lines, ui.Text("line-"+strconv.Itoa(i), line, ui.Attr{}))
+	}
+	return ui.Box(id, ui.WithDirection(ui.Column), ui.WithBorder(ui.Attr{FG: red}),
+		ui.WithPadding(ui.Padding{L: 1, R: 1}), ui.WithChildren(lines...))
+}
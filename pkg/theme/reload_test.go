@@ -0,0 +1,145 @@
+package theme
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui/uitest"
+)
+
+func TestReloadRepaintsChangedNodes(t *testing.T) {
+	load := func(src string) Theme {
+		th, err := Load([]byte(src), "yaml")
+		if err != nil { t.Fatal(err) }
+		return th
+	}
+	red := load("colors:\n  accent: \"#E5484D\"\n")
+	blue := load("colors:\n  accent: \"#2F6FDB\"\n")
+	none := load("name: plain\n") // accent removed
+
+	tree := ui.Box("root", ui.WithDirection(ui.Column), ui.WithChildren(
+		ui.Text("save", "Save", ui.Attr{Italic: true}, ui.WithClass("fg-accent bold")),
+		ui.Text("quit", "Quit", ui.Attr{}, ui.WithClass("fg-text")),
+	))
+	s := uitest.New(6, 2)
+	cur := red
+	Apply(tree, cur, Env{})
+	s.Render(tree)
+	uitest.AssertText(t, s, 0, 0, "Save")
+	uitest.AssertAttr(t, s, 0, 0, ui.Attr{FG: toTermColor("#E5484D"), Bold: true, Italic: true})
+
+	// reload follows the ReloadMsg recipe: Changed, Apply, Invalidate.
+	reload := func(next Theme, want ui.Attr) {
+		t.Helper()
+		stale := Changed(tree, cur, next, Env{})
+		if !reflect.DeepEqual(stale, []ui.Key{"root/save"}) { t.Errorf("Changed = %v, want [root/save]", stale) }
+		prev := cur
+		cur = next
+		Apply(tree, cur, Env{})
+		if after := Changed(tree, prev, next, Env{}); !reflect.DeepEqual(after, stale) { t.Errorf("Changed after Apply = %v, want %v", after, stale) }
+		s.Invalidate(stale...)
+		s.Render(tree)
+		uitest.AssertText(t, s, 0, 0, "Save")
+		for x := 0; x < 4; x++ { uitest.AssertAttr(t, s, x, 0, want) }
+		uitest.AssertAttr(t, s, 0, 1, ui.Attr{FG: toTermColor(DefaultTokens().Colors.Text)})
+	}
+	reload(blue, ui.Attr{FG: toTermColor("#2F6FDB"), Bold: true, Italic: true})
+	reload(none, ui.Attr{Bold: true, Italic: true})
+	if stale := Changed(tree, none, none, Env{}); len(stale) != 0 { t.Errorf("Changed with the same theme = %v", stale) }
+}
+
+func TestReloadDroppingValues(t *testing.T) {
+	load := func(src string) Theme {
+		th, err := Load([]byte(src), "yaml")
+		if err != nil { t.Fatal(err) }
+		return th
+	}
+	first := load("spacing:\n  card: 1\nbreakpoints:\n  tablet: 5\n")
+	second := load("name: plain\n") // card spacing and the tablet breakpoint removed
+	env := Env{Width: 10}
+	red := ui.Attr{BG: toTermColor("#E5484D")}
+
+	tree := ui.Box("root", ui.WithDirection(ui.Column), ui.WithChildren(
+		ui.Box("card", ui.WithClass("p-card tablet:max-w-6 bg-#E5484D"), ui.WithChildren(ui.Text("hi", "Hi", ui.Attr{}))),
+	))
+
+	s := uitest.New(10, 3)
+	Apply(tree, first, env)
+	s.Render(tree)
+	uitest.AssertText(t, s, 1, 1, "Hi")
+	uitest.AssertAttr(t, s, 5, 0, red)
+	uitest.AssertAttr(t, s, 6, 0, ui.Attr{})
+
+	stale := Changed(tree, first, second, env)
+	if !reflect.DeepEqual(stale, []ui.Key{"root/card"}) { t.Errorf("Changed = %v, want [root/card]", stale) }
+	Apply(tree, second, env)
+	s.Invalidate(stale...)
+	s.Render(tree)
+	uitest.AssertText(t, s, 0, 0, "Hi")
+	uitest.AssertAttr(t, s, 9, 0, red) // no max width any more
+	if _, ok := tree.Children()[0].Props()["padding"]; ok { t.Error("padding kept after its spacing token was removed") }
+}
+
+func TestWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "theme.yaml")
+	write := func(src string) {
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil { t.Fatal(err) }
+	}
+	write("colors:\n  accent: \"#E5484D\"\n")
+	w := NewWatcher(path)
+	w.Interval = time.Millisecond
+	run := func(cmd tea.Cmd) <-chan tea.Msg {
+		done := make(chan tea.Msg, 1)
+		go func() { done <- cmd() }()
+		return done
+	}
+	wait := func(done <-chan tea.Msg) tea.Msg {
+		t.Helper()
+		select {
+		case msg := <-done: return msg
+		case <-time.After(5 * time.Second): t.Fatal("Watch didn't return"); return nil
+		}
+	}
+
+	// The state when Watch is called counts as seen: only a later save reloads.
+	done := run(w.Watch(context.Background()))
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case msg := <-done: t.Fatalf("Watch returned %v before any save", msg)
+	default:
+	}
+	write("colors:\n  accent: \"#2F6FDB\"\n  extra: \"#000000\"\n")
+	msg, ok := wait(done).(ReloadMsg)
+	if !ok || msg.Err != nil || msg.Path != path || msg.Theme.Tokens.Palette["accent"] != "#2F6FDB" { t.Errorf("got %+v, want the saved theme", msg) }
+
+	done = run(w.Watch(context.Background()))
+	write("colors: [\n")
+	if msg := wait(done).(ReloadMsg); msg.Err == nil { t.Error("a bad save reloaded without an error") }
+
+	// An editor saving by delete and rename: the file goes missing while
+	// the save settles, which must not reload it early.
+	w.Interval = 50 * time.Millisecond
+	done = run(w.Watch(context.Background()))
+	valid := "colors:\n  accent: \"#2F6FDB\"\n"
+	for i, end := 0, time.Now().Add(200*time.Millisecond); time.Now().Before(end); i++ {
+		write(valid + strings.Repeat("#\n", i)) // keeps changing, so the save never settles
+		time.Sleep(time.Millisecond)
+	}
+	if err := os.Remove(path); err != nil { t.Fatal(err) }
+	time.Sleep(30 * time.Millisecond)
+	write(valid)
+	if msg := wait(done).(ReloadMsg); msg.Err != nil || msg.Theme.Tokens.Palette["accent"] != "#2F6FDB" { t.Errorf("got %+v across a delete and rename, want the saved theme", msg) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done = run(w.Watch(ctx))
+	cancel()
+	if msg := wait(done); msg != nil { t.Errorf("cancelled Watch returned %v, want nil", msg) }
+}
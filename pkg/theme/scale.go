@@ -0,0 +1,57 @@
+package theme
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+)
+
+// Scale derives a color scale from seed ("#RRGGBB"): name-10 (near white)
+// through name-90 (near black) in even OKLCH lightness steps, keeping seed's
+// hue and as much of its chroma as sRGB allows. seed itself takes the step
+// nearest its lightness, and name alone is an alias for it:
+//
+//	Scale("brand", "#2F6FDB") // brand, brand-10 … brand-90; brand-60 is #2F6FDB
+func Scale(name, seed string) (map[string]string, error) {
+	c, err := ui.Hex(seed)
+	if err != nil || !isHex(seed) { return nil, fmt.Errorf("theme: scale %q: invalid hex color %q (want #RRGGBB)", name, seed) }
+	r, g, b, _ := c.RGB()
+	L, C, H := renderer.OKLCH(r, g, b)
+	at := 10 * int(math.Round((scaleTop-L)/scaleStep/10)+1)
+	at = max(10, min(90, at))
+	out := map[string]string{name: seed}
+	for step := 10; step <= 90; step += 10 {
+		hex := seed
+		if step != at { hex = ui.RGB(renderer.FromOKLCH(scaleTop-float64(step-10)*scaleStep, C, H)).String() }
+		out[fmt.Sprintf("%s-%d", name, step)] = hex
+	}
+	return out, nil
+}
+
+// Scale lightness runs from scaleTop at step 10 down by scaleStep per unit,
+// reaching 0.25 at step 90.
+const scaleTop, scaleStep = 0.97, 0.009
+
+// WithScale returns th with the Scale of seed added to its palette, so
+// classes like "bg-brand-30" resolve.
+func (th Theme) WithScale(name, seed string) (Theme, error) {
+	s, err := Scale(name, seed)
+	if err != nil { return th, err }
+	var over Theme
+	over.Tokens.Palette = s
+	return Merge(th, over), nil
+}
+
+// semanticSeeds seed DefaultTokens' scales: brand is the default primary,
+// the rest are the semantic aliases. The scales are fixed palette entries,
+// so a theme that overrides primary keeps the pink brand scale; add its
+// own with WithScale.
+var semanticSeeds = map[string]string{
+	"brand":   "#F4ACB7",
+	"danger":  "#E5484D",
+	"warning": "#F5A524",
+	"success": "#30A46C",
+	"muted":   "#6F6E69",
+}
@@ -0,0 +1,56 @@
+package theme
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+)
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		seed string
+		at   int // step the seed lands on
+	}{
+		{"#2F6FDB", 60},
+		{"#F4ACB7", 30},
+		{"#B03A55", 60},
+		{"#E5484D", 50},
+		{"#6F6E69", 60},
+		{"#FFFFFF", 10},
+		{"#000000", 90},
+	}
+	for _, tt := range tests {
+		t.Run(tt.seed, func(t *testing.T) {
+			s, err := Scale("x", tt.seed)
+			if err != nil { t.Fatal(err) }
+			if len(s) != 10 || s["x"] != tt.seed { t.Errorf("got %d entries, alias %q", len(s), s["x"]) }
+			prev := 2.0
+			for step := 10; step <= 90; step += 10 {
+				hex := s[fmt.Sprintf("x-%d", step)]
+				if (hex == tt.seed) != (step == tt.at) { t.Errorf("x-%d = %s, want the seed at x-%d", step, hex, tt.at) }
+				c, err := ui.Hex(hex)
+				if err != nil { t.Fatalf("x-%d: %v", step, err) }
+				r, g, b, _ := c.RGB()
+				L, _, _ := renderer.OKLCH(r, g, b)
+				if L >= prev { t.Errorf("x-%d (%s) has lightness %.3f, not below the previous step's %.3f", step, hex, L, prev) }
+				prev = L
+			}
+		})
+	}
+}
+
+func TestScaleInvalidSeed(t *testing.T) {
+	for _, seed := range []string{"2F6FDB", "#fff", "#12345G", "pink-60", ""} {
+		if _, err := Scale("x", seed); err == nil || err.Error() != fmt.Sprintf("theme: scale \"x\": invalid hex color %q (want #RRGGBB)", seed) { t.Errorf("Scale(%q) err = %v", seed, err) }
+	}
+}
+
+func TestWithScale(t *testing.T) {
+	th, err := Default().WithScale("brand", "#2F6FDB")
+	if err != nil { t.Fatal(err) }
+	if got := th.Tokens.Color("brand-60"); got != "#2F6FDB" { t.Errorf("brand-60 = %s, want the seed", got) }
+	if got := Default().Tokens.Color("brand-30"); got != "#F4ACB7" { t.Errorf("default brand-30 = %s, want #F4ACB7", got) }
+	if got := th.Tokens.Color("danger-50"); got != "#E5484D" { t.Errorf("danger-50 = %s, want the default seed", got) }
+}
@@ -0,0 +1,98 @@
+package theme
+
+import (
+	"maps"
+
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+)
+
+// Props read by Apply. PropTheme rebinds tokens for a subtree, PropState is
+// a node's interaction state for class variants.
+var (
+	PropTheme = ui.NewPropKey[Theme]("theme")
+	PropState = ui.NewPropKey[State]("state")
+
+	// propBase keeps a node's styled props (see styledProps) from before
+	// its first Apply, so applying again (another theme, a reloaded file,
+	// another width or class) starts from them rather than from the
+	// previous result.
+	propBase = ui.NewPropKey[map[string]any]("theme-base")
+)
+
+// With makes a node a theme scope: overrides is merged (see Merge) over the
+// theme in effect for the node and its subtree.
+//
+//	ui.Box("danger-zone", theme.With(danger), ui.WithChildren(deleteButton))
+func With(overrides Theme) ui.NodeOption { return ui.Set(PropTheme, overrides) }
+
+// WithState sets the state a node's class variants see ("focus:bg-…").
+func WithState(s State) ui.NodeOption { return ui.Set(PropState, s) }
+
+// Apply resolves the class strings (ui.WithClass) in root's tree in place.
+// Each node uses th merged with every theme.With scope above it (itself
+// included), and variants match env with the node's own state added. Class
+// styles win over the node's other options; attributes are overlaid, so a
+// class that sets only fg keeps the node's bold.
+//
+// Applying again to the same tree starts over from each node's own props:
+// whatever a class no longer sets, because it changed, was cleared or its
+// variant stopped matching, goes back to what the node had before its
+// first Apply. Set a node's own styling before that first Apply.
+func Apply(root ui.Node, th Theme, env Env) {
+	if root != nil { apply(root, th, env) }
+}
+
+func apply(n ui.Node, th Theme, env Env) {
+	p := n.Props()
+	if over, ok := PropTheme.Lookup(p); ok { th = Merge(th, over) }
+	class, _ := ui.PropClass.Lookup(p)
+	if _, styled := propBase.Lookup(p); styled || class != "" {
+		out := th.styled(n, class, env, baseProps(p))
+		for _, name := range styledProps { delete(p, name) }
+		maps.Copy(p, out)
+	}
+	for _, k := range n.Children() { apply(k, th, env) }
+}
+
+// styledProps are the props class options set (see ResolvedTUI.Options).
+var styledProps = []string{
+	ui.PropAttr.Name(), ui.PropPadding.Name(), ui.PropMargin.Name(), ui.PropRadius.Name(), ui.PropBorder.Name(),
+	ui.PropW.Name(), ui.PropH.Name(), ui.PropMinW.Name(), ui.PropMaxW.Name(), ui.PropMinH.Name(), ui.PropMaxH.Name(),
+	ui.PropWPercent.Name(), ui.PropHPercent.Name(), ui.PropAspect.Name(),
+	ui.PropGap.Name(), ui.PropGrow.Name(), ui.PropShrink.Name(), ui.PropBasis.Name(),
+	ui.PropDirection.Name(), ui.PropAlign.Name(), ui.PropJustify.Name(),
+}
+
+// styled returns the styled props Apply gives n: base with n's class,
+// resolved under th, applied over it.
+func (th Theme) styled(n ui.Node, class string, env Env, base map[string]any) map[string]any {
+	scratch := ui.Box(string(n.ID()))
+	p := scratch.Props()
+	maps.Copy(p, base)
+	if class == "" { return p }
+	env.State |= PropState.Or(n, 0)
+	spec := th.For(ParseClass(class), env)
+	prev, had := ui.PropAttr.Lookup(p)
+	attr := th.overlayAttr(prev, spec)
+	ui.Apply(scratch, th.ResolveTUI(spec).Options()...)
+	if had || attr != (ui.Attr{}) { ui.PropAttr.Set(p, attr) }
+	return p
+}
+
+// baseProps returns n's styled props from before Apply first styled it,
+// recording them on the first call.
+func baseProps(p map[string]any) map[string]any {
+	if b, ok := propBase.Lookup(p); ok { return b }
+	b := ownProps(p)
+	propBase.Set(p, b)
+	return b
+}
+
+// ownProps copies the styled props out of p.
+func ownProps(p map[string]any) map[string]any {
+	b := map[string]any{}
+	for _, name := range styledProps {
+		if v, ok := p[name]; ok { b[name] = v }
+	}
+	return b
+}
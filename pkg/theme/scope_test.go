@@ -0,0 +1,71 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+)
+
+func TestApplyAgainStartsFromOriginalAttr(t *testing.T) {
+	red, blue := toTermColor("#E5484D"), toTermColor("#2F6FDB")
+	var redTheme, blueTheme Theme
+	redTheme.Tokens.Colors.Primary, blueTheme.Tokens.Colors.Primary = "#E5484D", "#2F6FDB"
+	redTheme, blueTheme = Merge(Default(), redTheme), Merge(Default(), blueTheme)
+
+	label := ui.Text("label", "Save", ui.Attr{Italic: true}, ui.WithClass("fg-primary bold"))
+	plain := ui.Text("plain", "Quit", ui.Attr{}, ui.WithClass("fg-primary"))
+	root := ui.Box("root", ui.WithChildren(label, plain))
+	attr := func(n ui.Node) ui.Attr { return ui.PropAttr.Or(n, ui.Attr{}) }
+
+	Apply(root, redTheme, Env{})
+	if got, want := attr(label), (ui.Attr{FG: red, Bold: true, Italic: true}); got != want { t.Fatalf("first Apply: %+v, want %+v", got, want) }
+	Apply(root, blueTheme, Env{})
+	if got, want := attr(label), (ui.Attr{FG: blue, Bold: true, Italic: true}); got != want { t.Errorf("second Apply: %+v, want %+v", got, want) }
+
+	// Dropping classes drops what they set, back to the node's own attr.
+	ui.PropClass.Set(label.Props(), "underline")
+	ui.PropClass.Set(plain.Props(), "p-1")
+	Apply(root, blueTheme, Env{})
+	if got, want := attr(label), (ui.Attr{Italic: true, Underline: true}); got != want { t.Errorf("after class change: %+v, want %+v", got, want) }
+	if got := attr(plain); got != (ui.Attr{}) { t.Errorf("plain kept %+v", got) }
+
+	if err := ui.Validate(root); err != nil { t.Errorf("Validate: %v", err) }
+}
+
+func TestApplyAgainRestoresOwnProps(t *testing.T) {
+	wide, narrow := Env{Width: 100}, Env{Width: 40}
+	tests := []struct {
+		name          string
+		opts          []ui.NodeOption
+		class         string // before the second Apply
+		first, second Env
+		want          map[string]any // props after the second Apply; nil = unset
+	}{
+		{"breakpoint stops matching", []ui.NodeOption{ui.WithClass("md:p-4 w-10")}, "md:p-4 w-10", wide, narrow,
+			map[string]any{"padding": nil, "w": 10}},
+		{"breakpoint keeps own padding", []ui.NodeOption{ui.WithPadding(ui.Padding{L: 1}), ui.WithClass("md:p-4")}, "md:p-4", wide, narrow,
+			map[string]any{"padding": ui.Padding{L: 1}}},
+		{"breakpoint starts matching", []ui.NodeOption{ui.WithClass("md:p-4")}, "md:p-4", narrow, wide,
+			map[string]any{"padding": ui.Padding{T: 4, R: 4, B: 4, L: 4}}},
+		{"cleared class", []ui.NodeOption{ui.WithClass("bold gap-2 justify-center")}, "", wide, wide,
+			map[string]any{"attr": nil, "gap": nil, "justify": nil}},
+		{"cleared class keeps own attr", []ui.NodeOption{ui.WithAttr(ui.Attr{Italic: true}), ui.WithClass("bold")}, "", wide, wide,
+			map[string]any{"attr": ui.Attr{Italic: true}}},
+		{"changed class", []ui.NodeOption{ui.WithMargin(ui.Padding{T: 1}), ui.WithClass("m-2 grow")}, "shrink", wide, wide,
+			map[string]any{"margin": ui.Padding{T: 1}, "grow": nil, "shrink": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := ui.Box("n", tt.opts...)
+			Apply(n, Default(), tt.first)
+			ui.PropClass.Set(n.Props(), tt.class)
+			Apply(n, Default(), tt.second)
+			for name, want := range tt.want {
+				got, ok := n.Props()[name]
+				if want == nil && ok { t.Errorf("%s = %v, want unset", name, got) }
+				if want != nil && got != want { t.Errorf("%s = %v, want %v", name, got, want) }
+			}
+			if err := ui.Validate(n); err != nil { t.Errorf("Validate: %v", err) }
+		})
+	}
+}
@@ -1,14 +1,23 @@
 package theme
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+	"github.com/charmbracelet/lipgloss"
+)
 
 // Theme bundles Tokens and exposes resolvers for both render paths.
 type Theme struct {
 	Name   string
 	Tokens Tokens
+
+	// Variants are named partial themes ("dark", "high-contrast") that
+	// Variant merges on top.
+	Variants map[string]Theme
 }
 
-func Default() Theme { return Theme{Name: "strawberry", Tokens: DefaultTokens()} }
+// Default is the light strawberry theme, with "dark" and "high-contrast"
+// variants (see Appearance).
+func Default() Theme { return Theme{Name: "strawberry", Tokens: DefaultTokens(), Variants: defaultVariants()} }
 
 // ---------------- Immediate-mode resolver (Lipgloss) ----------------
 
@@ -22,67 +31,184 @@ func (th Theme) ResolveLipgloss(base lipgloss.Style, spec StyleSpec) lipgloss.St
 	if spec.BorderHex   != "" { s = s.BorderForeground(lipgloss.Color(spec.BorderHex)) }
 	if spec.BorderToken != "" { s = s.BorderForeground(lipgloss.Color(th.Tokens.Color(spec.BorderToken))) }
 
-	// Padding
-	pt, pr, pb, pl := padFrom(spec)
+	// Padding and margins; gap and flex utilities only reach the retained-mode path
+	pt, pr, pb, pl := th.padFrom(spec)
 	s = s.Padding(pt, pr, pb, pl)
+	mt, mr, mb, ml := th.marginFrom(spec)
+	s = s.Margin(mt, mr, mb, ml)
 
 	// Text attrs
 	if spec.Bold != nil      { s = s.Bold(*spec.Bold) }
 	if spec.Underline != nil { s = s.Underline(*spec.Underline) }
+	if spec.Italic != nil    { s = s.Italic(*spec.Italic) }
+	if spec.Dim != nil       { s = s.Faint(*spec.Dim) }
+	if spec.Strikethrough != nil { s = s.Strikethrough(*spec.Strikethrough) }
+	if spec.Reverse != nil   { s = s.Reverse(*spec.Reverse) }
+	if spec.Blink != nil     { s = s.Blink(*spec.Blink) }
+
+	// Sizing: lipgloss widths include padding; it has no min sizes, percentages
+	// or aspect ratios (those need the parent's size, i.e. the layout engine).
+	if spec.W != nil    { s = s.Width(*spec.W) }
+	if spec.H != nil    { s = s.Height(*spec.H) }
+	if spec.MaxW != nil { s = s.MaxWidth(*spec.MaxW) }
+	if spec.MaxH != nil { s = s.MaxHeight(*spec.MaxH) }
 
 	// Note: Lipgloss doesn't have corner radius; choose border style per radius if desired.
+	// Nor does it style or color underlines; those only reach the retained-mode path.
 	return s
 }
 
-func padFrom(spec StyleSpec) (pt, pr, pb, pl int) {
-	if spec.P  != nil { pt, pr, pb, pl = *spec.P, *spec.P, *spec.P, *spec.P }
-	if spec.Px != nil { pr, pl = *spec.Px, *spec.Px }
-	if spec.Py != nil { pt, pb = *spec.Py, *spec.Py }
-	if spec.Pt != nil { pt = *spec.Pt }
-	if spec.Pr != nil { pr = *spec.Pr }
-	if spec.Pb != nil { pb = *spec.Pb }
-	if spec.Pl != nil { pl = *spec.Pl }
+func (th Theme) padFrom(spec StyleSpec) (pt, pr, pb, pl int) {
+	return th.sides(spec.P, spec.Px, spec.Py, spec.Pt, spec.Pr, spec.Pb, spec.Pl)
+}
+
+func (th Theme) marginFrom(spec StyleSpec) (mt, mr, mb, ml int) {
+	return th.sides(spec.M, spec.Mx, spec.My, spec.Mt, spec.Mr, spec.Mb, spec.Ml)
+}
+
+// sides resolves all/x/y/per-side spacing values, most specific last.
+func (th Theme) sides(all, x, y, t, r, b, l string) (st, sr, sb, sl int) {
+	sp := th.Tokens.Spacing
+	if all != "" { st, sr, sb, sl = sp(all), sp(all), sp(all), sp(all) }
+	if x != "" { sr, sl = sp(x), sp(x) }
+	if y != "" { st, sb = sp(y), sp(y) }
+	if t != "" { st = sp(t) }
+	if r != "" { sr = sp(r) }
+	if b != "" { sb = sp(b) }
+	if l != "" { sl = sp(l) }
 	return
 }
 
 // ---------------- Retained-mode resolver (to your UI attrs) ----------------
 
-// Adapt these to your retained-mode types.
+// ResolvedTUI carries ready-to-use values for ui.With* options.
 type ResolvedTUI struct {
-	Attr struct {
-		FG, BG int16
-		Bold, Underline bool
-	}
-	Padding struct{ T, R, B, L int }
+	Attr      ui.Attr
+	Padding   ui.Padding
+	Margin    ui.Padding
 	Radius    int
 	BorderHex string
+	Border    ui.Color // BorderHex as a color, for ui.WithBorder
+
+	// Sizing in cells (nil = unset); WPercent/HPercent are percentages of the
+	// parent's content box (0 = unset), Aspect is width÷height (0 = unset).
+	W, H, MinW, MaxW, MinH, MaxH *int
+	WPercent, HPercent           int
+	Aspect                       float64
+
+	// Flex (nil = unset): Gap and Direction for containers, Grow, Shrink
+	// and Basis for items.
+	Gap, Grow, Shrink, Basis *int
+	Direction                *ui.Direction
+	Align                    *ui.Align
+	Justify                  *ui.Justify
 }
 
 func (th Theme) ResolveTUI(spec StyleSpec) ResolvedTUI {
 	var r ResolvedTUI
 
-	// Resolve colors: prefer explicit hex, else token lookup
-	fg := pickHex(spec.FGHex,   th.Tokens.Color(spec.FGToken))
-	bg := pickHex(spec.BGHex,   th.Tokens.Color(spec.BGToken))
+	r.Attr = th.overlayAttr(ui.Attr{}, spec)
 	bc := pickHex(spec.BorderHex, th.Tokens.Color(spec.BorderToken))
-
-	r.Attr.FG = toTermColor(fg) // TODO: hex→256-color mapping (or -1 for default)
-	r.Attr.BG = toTermColor(bg)
 	r.BorderHex = bc
+	r.Border = toTermColor(bc)
 
-	if spec.Bold != nil      { r.Attr.Bold      = *spec.Bold }
-	if spec.Underline != nil { r.Attr.Underline = *spec.Underline }
-
-	pt, pr, pb, pl := padFrom(spec)
-	r.Padding = struct{T,R,B,L int}{pt, pr, pb, pl}
+	pt, pr, pb, pl := th.padFrom(spec)
+	r.Padding = ui.Padding{T: pt, R: pr, B: pb, L: pl}
+	mt, mr, mb, ml := th.marginFrom(spec)
+	r.Margin = ui.Padding{T: mt, R: mr, B: mb, L: ml}
 
 	// Radius from class or token
 	if spec.Radius != nil { r.Radius = *spec.Radius
 	} else if spec.RadiusKey != "" { r.Radius = th.Tokens.RadiusVal(spec.RadiusKey)
 	}
 
+	r.W, r.H, r.MinW, r.MaxW, r.MinH, r.MaxH = spec.W, spec.H, spec.MinW, spec.MaxW, spec.MinH, spec.MaxH
+	if spec.WPct != nil   { r.WPercent = *spec.WPct }
+	if spec.HPct != nil   { r.HPercent = *spec.HPct }
+	if spec.Aspect != nil { r.Aspect = *spec.Aspect }
+
+	space := func(v string) *int { if v == "" { return nil }; n := th.Tokens.Spacing(v); return &n }
+	r.Gap, r.Basis = space(spec.Gap), space(spec.Basis)
+	r.Grow, r.Shrink = spec.Grow, spec.Shrink
+	switch spec.Direction {
+	case "row": d := ui.Row; r.Direction = &d
+	case "col": d := ui.Column; r.Direction = &d
+	}
+	if a, ok := alignOf[spec.Items]; ok { r.Align = &a }
+	if j, ok := justifyOf[spec.Justify]; ok { r.Justify = &j }
+
 	return r
 }
 
+// overlayAttr sets the attributes spec names on a, leaving the rest.
+func (th Theme) overlayAttr(a ui.Attr, spec StyleSpec) ui.Attr {
+	// Resolve colors: prefer explicit hex, else token lookup
+	// (truecolor; engines downsample per terminal profile)
+	if fg := pickHex(spec.FGHex, th.Tokens.Color(spec.FGToken)); fg != "" { a.FG = toTermColor(fg) }
+	if bg := pickHex(spec.BGHex, th.Tokens.Color(spec.BGToken)); bg != "" { a.BG = toTermColor(bg) }
+
+	if spec.Bold != nil      { a.Bold      = *spec.Bold }
+	if spec.Underline != nil { a.Underline = *spec.Underline }
+	if spec.Italic != nil    { a.Italic    = *spec.Italic }
+	if spec.Dim != nil       { a.Dim       = *spec.Dim }
+	if spec.Strikethrough != nil { a.Strikethrough = *spec.Strikethrough }
+	if spec.Reverse != nil   { a.Reverse   = *spec.Reverse }
+	if spec.Blink != nil     { a.Blink     = *spec.Blink }
+	if spec.UnderlineStyle != "" { a.UnderlineStyle = underlineStyleOf(spec.UnderlineStyle) }
+	if uc := pickHex(spec.UnderlineHex, th.Tokens.Color(spec.UnderlineToken)); uc != "" { a.UnderlineColor = toTermColor(uc) }
+	return a
+}
+
+// Options turns r into node options. Only what the classes set is applied,
+// so a class list without colors doesn't paint a background.
+func (r ResolvedTUI) Options() []ui.NodeOption {
+	var opts []ui.NodeOption
+	if r.Attr != (ui.Attr{}) { opts = append(opts, ui.WithAttr(r.Attr)) }
+	if r.Padding != (ui.Padding{}) { opts = append(opts, ui.WithPadding(r.Padding)) }
+	if r.Margin != (ui.Padding{}) { opts = append(opts, ui.WithMargin(r.Margin)) }
+	if r.Radius != 0 { opts = append(opts, ui.WithRadius(r.Radius)) }
+	if r.BorderHex != "" { opts = append(opts, ui.WithBorder(ui.Attr{FG: r.Border})) }
+	set := func(v *int, opt func(int) ui.NodeOption) {
+		if v != nil { opts = append(opts, opt(*v)) }
+	}
+	set(r.W, func(n int) ui.NodeOption { return ui.Set(ui.PropW, n) })
+	set(r.H, func(n int) ui.NodeOption { return ui.Set(ui.PropH, n) })
+	set(r.MinW, ui.WithMinWidth)
+	set(r.MaxW, ui.WithMaxWidth)
+	set(r.MinH, ui.WithMinHeight)
+	set(r.MaxH, ui.WithMaxHeight)
+	if r.WPercent > 0 { opts = append(opts, ui.WithWidthPercent(r.WPercent)) }
+	if r.HPercent > 0 { opts = append(opts, ui.WithHeightPercent(r.HPercent)) }
+	if r.Aspect > 0 { opts = append(opts, ui.WithAspectRatio(r.Aspect)) }
+	set(r.Gap, ui.WithGap)
+	set(r.Grow, func(n int) ui.NodeOption { return ui.Set(ui.PropGrow, n) })
+	set(r.Shrink, func(n int) ui.NodeOption { return ui.Set(ui.PropShrink, n) })
+	set(r.Basis, func(n int) ui.NodeOption { return ui.Set(ui.PropBasis, n) })
+	if r.Direction != nil { opts = append(opts, ui.WithDirection(*r.Direction)) }
+	if r.Align != nil { opts = append(opts, ui.WithAlign(*r.Align)) }
+	if r.Justify != nil { opts = append(opts, ui.WithJustify(*r.Justify)) }
+	return opts
+}
+
+var (
+	alignOf = map[string]ui.Align{
+		"stretch": ui.AlignStretch, "start": ui.AlignStart, "end": ui.AlignEnd, "center": ui.AlignCenter,
+	}
+	justifyOf = map[string]ui.Justify{
+		"start": ui.JustifyStart, "end": ui.JustifyEnd, "center": ui.JustifyCenter,
+		"between": ui.JustifyBetween, "around": ui.JustifyAround, "evenly": ui.JustifyEvenly,
+	}
+)
+
+func underlineStyleOf(name string) ui.UnderlineStyle {
+	switch name {
+	case "double": return ui.UnderlineDouble
+	case "curly":  return ui.UnderlineCurly
+	case "dotted": return ui.UnderlineDotted
+	case "dashed": return ui.UnderlineDashed
+	}
+	return ui.UnderlineSingle
+}
+
 func pickHex(hex, tokenHex string) string { if hex != "" { return hex }; return tokenHex }
-func toTermColor(hex string) int16        { if hex == "" { return -1 }; return -1 /* map later */ }
+func toTermColor(hex string) ui.Color     { c, _ := ui.Hex(hex); return c } // bad/empty hex → default
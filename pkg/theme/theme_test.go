@@ -0,0 +1,25 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+)
+
+func TestResolveBareHexIsAToken(t *testing.T) {
+	var th Theme
+	th.Tokens.Palette = map[string]string{"fed": "#2F6FDB"}
+	th = Merge(Default(), th)
+	tests := []struct {
+		class string
+		fg    ui.Color
+	}{
+		{"fg-#fed", ui.RGB(0xFF, 0xEE, 0xDD)},
+		{"fg-fed", ui.RGB(0x2F, 0x6F, 0xDB)}, // the palette entry
+		{"fg-bad", ui.DefaultColor},          // unknown token, not #BBAADD
+		{"fg-decade", ui.DefaultColor},
+	}
+	for _, tt := range tests {
+		if got := th.ResolveTUI(ParseClass(tt.class)).Attr.FG; got != tt.fg { t.Errorf("%s: fg %v, want %v", tt.class, got, tt.fg) }
+	}
+}
@@ -1,15 +1,22 @@
 package theme
 
+import (
+	"maps"
+	"strconv"
+)
+
 // Tokens are raw design values. No Lipgloss here.
 type Tokens struct {
 	Colors struct {
-		Bg, Surface, Text string
+		Bg, Surface, Text  string
 		Primary, PrimaryFg string
 	}
-	Space  map[string]int        // spacing scale (cells)
-	Radius map[string]int        // rounded corners (cells)
-	Border struct{ Normal, Focused string }
-	Motion Motion                // defined in motion.go
+	Palette     map[string]string // named colors for class tokens ("pink-50" → "#FFCAD4")
+	Space       map[string]int    // spacing scale (cells)
+	Radius      map[string]int    // rounded corners (cells)
+	Breakpoints map[string]int    // min terminal columns for "sm:", "md:", ... variants
+	Border      struct{ Normal, Focused string }
+	Motion      Motion // defined in motion.go
 }
 
 func DefaultTokens() Tokens {
@@ -22,8 +29,22 @@ func DefaultTokens() Tokens {
 	t.Colors.Primary   = "#F4ACB7" // 3
 	t.Colors.PrimaryFg = "#3f0d12" // 4
 
+	t.Palette = map[string]string{
+		"white":       "#FFFFFF",
+		"black":       "#000000",
+		"pink-50":     "#FFCAD4",
+		"pink-60":     "#F4ACB7",
+		"maroon-90":   "#3f0d12",
+		"graphite-90": "#242423",
+	}
+	// brand-10 … brand-90, danger-*, warning-*, success-*, muted-* (scale.go)
+	for name, seed := range semanticSeeds {
+		s, _ := Scale(name, seed)
+		maps.Copy(t.Palette, s)
+	}
 	t.Space  = map[string]int{"xs":1, "sm":2, "md":4, "lg":6, "xl":8}
 	t.Radius = map[string]int{"none":0, "sm":0, "md":1, "lg":2}
+	t.Breakpoints = map[string]int{"sm":60, "md":80, "lg":120}
 	t.Border.Normal  = t.Colors.Text
 	t.Border.Focused = t.Colors.PrimaryFg
 
@@ -32,17 +53,57 @@ func DefaultTokens() Tokens {
 	return t
 }
 
+// DarkTokens is DefaultTokens for dark terminals: graphite background,
+// white text, the same pink primary.
+func DarkTokens() Tokens { return Default().Variant("dark").Tokens }
+
+// HighContrastTokens is DefaultTokens on black with white text and the
+// lightest pink as primary.
+func HighContrastTokens() Tokens { return Default().Variant("high-contrast").Tokens }
+
+// defaultVariants are Default's "dark" and "high-contrast" variants. They
+// only rebind roles and borders, so themes built on Default keep their own
+// palette and primary in dark mode unless they override these too.
+func defaultVariants() map[string]Theme {
+	var dark, hc Theme
+	c := &dark.Tokens.Colors
+	c.Bg, c.Surface, c.Text = "graphite-90", "maroon-90", "white"
+	dark.Tokens.Border.Focused = "pink-60"
+
+	c = &hc.Tokens.Colors
+	c.Bg, c.Surface, c.Text = "black", "black", "white"
+	c.Primary, c.PrimaryFg = "pink-50", "black"
+	hc.Tokens.Border.Normal, hc.Tokens.Border.Focused = "white", "pink-50"
+	return map[string]Theme{"dark": dark, "high-contrast": hc}
+}
+
 // Optional helpers (token lookups)
+
+// Color returns the hex for a palette name or a role ("primary",
+// "primary-fg", "bg", "surface", "text"); palette names win. Roles let a
+// theme scope rebind what "fg-primary" means.
 func (t Tokens) Color(name string) string {
-	switch name {
-	case "white": return "#FFFFFF"
-	case "pink-50": return "#FFCAD4"
-	case "pink-60": return "#F4ACB7"
-	case "maroon-90": return "#3f0d12"
-	case "graphite-90": return "#242423"
-	}
+	if hex, ok := t.Palette[name]; ok { return hex }
+	if hex, ok := t.roles()[name]; ok { return hex }
 	// allow direct pass-through of unknowns
 	return name
 }
+// HasColor reports whether name is a palette color or a role.
+func (t Tokens) HasColor(name string) bool { return t.Color(name) != name }
+// ColorNames lists the palette colors and roles, sorted.
+func (t Tokens) ColorNames() []string { return keys(union(t.roles(), t.Palette)) }
+
+func (t Tokens) roles() map[string]string {
+	return map[string]string{
+		"bg": t.Colors.Bg, "surface": t.Colors.Surface, "text": t.Colors.Text,
+		"primary": t.Colors.Primary, "primary-fg": t.Colors.PrimaryFg,
+	}
+}
 func (t Tokens) SpaceVal(key string) int  { if v,ok:=t.Space[key]; ok {return v}; return 0 }
+// Spacing resolves a class spacing value: a Space key ("md") or raw cells ("2").
+func (t Tokens) Spacing(v string) int {
+	if n, ok := t.Space[v]; ok { return n }
+	n, _ := strconv.Atoi(v)
+	return n
+}
 func (t Tokens) RadiusVal(key string) int { if v,ok:=t.Radius[key]; ok {return v}; return 0 }
@@ -0,0 +1,135 @@
+package theme
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// State is the interaction state of a component, matched by state variant
+// prefixes. States combine: Focused|Active.
+type State uint8
+
+const (
+	Focused  State = 1 << iota // "focus:"
+	Disabled                   // "disabled:"
+	Hovered                    // "hover:"
+	Active                     // "active:"
+)
+
+var statePrefixes = map[string]State{"focus": Focused, "disabled": Disabled, "hover": Hovered, "active": Active}
+
+// Variant is a StyleSpec delta that applies when every state in States is
+// set and the terminal is at least Tokens.Breakpoints[Breakpoint] columns
+// wide ("" = any width). Prefixes stack: "md:focus:bold".
+type Variant struct {
+	States     State
+	Breakpoint string
+	Spec       StyleSpec
+}
+
+// Env is what variants are matched against: the component's state and the
+// terminal width in columns (0 = unknown, no breakpoint applies).
+type Env struct {
+	State State
+	Width int
+}
+
+// variant returns the spec the utilities under prefixes ("md:focus") go
+// to, creating its Variant on first use, and a key naming the condition.
+// It returns nil for an unknown prefix.
+func (p *classParser) variant(spec *StyleSpec, prefixes string) (*StyleSpec, string) {
+	var v Variant
+	for _, pre := range strings.Split(prefixes, ":") {
+		if st, ok := statePrefixes[pre]; ok { v.States |= st; continue }
+		if v.Breakpoint != "" && v.Breakpoint != pre { p.failf("more than one breakpoint in %q", prefixes); return nil, "" }
+		if p.tokens != nil {
+			if _, ok := p.tokens.Breakpoints[pre]; !ok {
+				p.failf("unknown variant %q%s", pre, didYouMean(pre, append(keys(statePrefixes), keys(p.tokens.Breakpoints)...)))
+				return nil, ""
+			}
+		}
+		v.Breakpoint = pre
+	}
+	for i := range spec.Variants {
+		if w := &spec.Variants[i]; w.States == v.States && w.Breakpoint == v.Breakpoint { return &w.Spec, v.key() }
+	}
+	spec.Variants = append(spec.Variants, v)
+	return &spec.Variants[len(spec.Variants)-1].Spec, v.key()
+}
+
+func (v Variant) key() string { return fmt.Sprintf("%s/%d:", v.Breakpoint, v.States) }
+
+// For flattens spec for env: the base properties overlaid with every
+// variant whose condition holds. Breakpoint-only variants apply narrowest
+// first, then state variants (again narrowest first), so a state always
+// beats a width and a wider breakpoint beats a narrower one.
+func (th Theme) For(spec StyleSpec, env Env) StyleSpec {
+	var on []Variant
+	for _, v := range spec.Variants {
+		if v.States&env.State != v.States { continue }
+		if v.Breakpoint != "" {
+			minW, ok := th.Tokens.Breakpoints[v.Breakpoint]
+			if !ok || env.Width <= 0 || env.Width < minW { continue }
+		}
+		on = append(on, v)
+	}
+	sort.SliceStable(on, func(i, j int) bool {
+		if (on[i].States != 0) != (on[j].States != 0) { return on[j].States != 0 }
+		return th.Tokens.Breakpoints[on[i].Breakpoint] < th.Tokens.Breakpoints[on[j].Breakpoint]
+	})
+	out := spec
+	out.Variants = nil
+	for _, v := range on { out = out.Merge(v.Spec) }
+	return out
+}
+
+// ResolveTUIFor resolves spec with the variants that match env.
+func (th Theme) ResolveTUIFor(spec StyleSpec, env Env) ResolvedTUI { return th.ResolveTUI(th.For(spec, env)) }
+
+// ResolveLipglossFor resolves spec onto base with the variants that match env.
+func (th Theme) ResolveLipglossFor(base lipgloss.Style, spec StyleSpec, env Env) lipgloss.Style {
+	return th.ResolveLipgloss(base, th.For(spec, env))
+}
+
+// Merge returns s with every property over sets taken from over; s's
+// Variants are kept. A color replaces both its token and hex forms, and a
+// size both its cell and percent forms, so "bg-#FFFFFF" then
+// "focus:bg-pink-60" ends on pink.
+func (s StyleSpec) Merge(over StyleSpec) StyleSpec {
+	dst, src := reflect.ValueOf(&s).Elem(), reflect.ValueOf(over)
+	for _, group := range specGroups {
+		set := false
+		for _, i := range group { set = set || !src.Field(i).IsZero() }
+		if !set { continue }
+		for _, i := range group { dst.Field(i).Set(src.Field(i)) }
+	}
+	return s
+}
+
+// specGroups lists StyleSpec field indexes by property; fields describing
+// one property in different forms share a group.
+var specGroups = func() [][]int {
+	pairs := [][]string{
+		{"FGHex", "FGToken"}, {"BGHex", "BGToken"}, {"BorderHex", "BorderToken"},
+		{"UnderlineHex", "UnderlineToken"}, {"RadiusKey", "Radius"}, {"W", "WPct"}, {"H", "HPct"},
+	}
+	t := reflect.TypeOf(StyleSpec{})
+	grouped := map[string]bool{"Variants": true}
+	var out [][]int
+	for _, pair := range pairs {
+		var g []int
+		for _, name := range pair {
+			f, _ := t.FieldByName(name)
+			g, grouped[name] = append(g, f.Index[0]), true
+		}
+		out = append(out, g)
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if !grouped[t.Field(i).Name] { out = append(out, []int{i}) }
+	}
+	return out
+}()
@@ -0,0 +1,48 @@
+package theme
+
+import "testing"
+
+func TestForPrecedence(t *testing.T) {
+	var narrowMd Theme // a scope moving md down to 40 columns
+	narrowMd.Tokens.Breakpoints = map[string]int{"md": 40}
+	scoped := Merge(Default(), narrowMd)
+
+	tests := []struct {
+		name  string
+		th    Theme // zero: Default()
+		class string
+		env   Env
+		want  string // FGToken after For
+	}{
+		{"base", Theme{}, "fg-text", Env{}, "text"},
+		{"state off", Theme{}, "fg-text focus:fg-primary", Env{}, "text"},
+		{"state on", Theme{}, "fg-text focus:fg-primary", Env{State: Focused}, "primary"},
+		{"every state needed", Theme{}, "fg-text focus:active:fg-primary", Env{State: Focused}, "text"},
+		{"every state set", Theme{}, "fg-text focus:active:fg-primary", Env{State: Focused | Active}, "primary"},
+		{"below breakpoint", Theme{}, "fg-text md:fg-primary", Env{Width: 79}, "text"},
+		{"at breakpoint", Theme{}, "fg-text md:fg-primary", Env{Width: 80}, "primary"},
+		{"unknown width", Theme{}, "fg-text md:fg-primary", Env{}, "text"},
+		{"scope breakpoint", scoped, "fg-text md:fg-primary", Env{Width: 50}, "primary"},
+		{"wider breakpoint wins", Theme{}, "lg:fg-muted md:fg-primary", Env{Width: 130}, "muted"},
+		{"wider breakpoint wins in any order", Theme{}, "md:fg-primary lg:fg-muted", Env{Width: 130}, "muted"},
+		{"state beats breakpoint", Theme{}, "focus:fg-primary lg:fg-muted", Env{Width: 130, State: Focused}, "primary"},
+		{"state at a width beats plain state", Theme{}, "md:focus:fg-muted focus:fg-primary", Env{Width: 90, State: Focused}, "muted"},
+		{"state at a width needs the width", Theme{}, "md:focus:fg-muted focus:fg-primary", Env{Width: 60, State: Focused}, "primary"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th := tt.th
+			if th.Tokens.Breakpoints == nil { th = Default() }
+			got := th.For(ParseClass(tt.class), tt.env)
+			if got.FGToken != tt.want { t.Errorf("fg = %q, want %q", got.FGToken, tt.want) }
+			if got.Variants != nil { t.Errorf("For kept %d variants", len(got.Variants)) }
+		})
+	}
+}
+
+func TestForKeepsUnrelatedProperties(t *testing.T) {
+	got := Default().For(ParseClass("bold fg-text md:fg-primary focus:underline"), Env{Width: 100, State: Focused})
+	if got.Bold == nil || !*got.Bold || got.Underline == nil || !*got.Underline || got.FGToken != "primary" {
+		t.Errorf("For = bold %v, underline %v, fg %q; want all three set", got.Bold, got.Underline, got.FGToken)
+	}
+}
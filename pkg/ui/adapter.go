@@ -1 +1,104 @@
-package ui
\ No newline at end of file
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/backend"
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/pipeline"
+)
+
+// NewANSIEngine returns an Engine that renders frames as ANSI strings,
+// w×h until the first Reconcile.
+//
+// The bounds passed to Reconcile set the frame size: resize by passing the
+// new size (e.g. from tea.WindowSizeMsg), which repaints the frame in full.
+// The engine diffs against the last tree it planned and keeps its cell
+// buffer itself, so it ignores Reconcile's prev: value-receiver View methods
+// that cannot hold on to their previous tree may pass nil. Colors are
+// downsampled to DetectProfile(); use ProfileSetter to override.
+func NewANSIEngine(w, h int) (Engine, error) {
+	if w < 0 || h < 0 { return nil, fmt.Errorf("ui: invalid engine size %dx%d", w, h) }
+	b := backend.NewANSI(w, h)
+	b.SetProfile(DetectProfile())
+	return &ansiEngine{p: pipeline.New(b), b: b}, nil
+}
+
+var (
+	_ ProblemReporter = (*ansiEngine)(nil)
+	_ ProblemReporter = (*tcellEngine)(nil)
+	_ Invalidator     = (*ansiEngine)(nil)
+	_ Invalidator     = (*tcellEngine)(nil)
+)
+
+type ansiEngine struct {
+	p *pipeline.Pipeline
+	b *backend.ANSI
+}
+
+func (e *ansiEngine) Reconcile(prev, next Node, bounds Rect) RenderPlan {
+	e.p.Validate = DevMode()
+	return e.p.Plan(next, bounds)
+}
+
+// Problems lists the prop errors in the last tree (dev mode only).
+func (e *ansiEngine) Problems() []error { return problems(e.p.Problems()) }
+
+// Commit applies plan and returns the whole frame for Bubble Tea's View().
+func (e *ansiEngine) Commit(plan RenderPlan) string {
+	e.CommitDiff(plan)
+	return e.b.Frame()
+}
+
+// CommitDiff applies plan and returns only the escape sequence that updates
+// the previous frame, for programs writing to the terminal themselves.
+func (e *ansiEngine) CommitDiff(plan RenderPlan) string {
+	e.p.Apply(plan)
+	return e.b.Flush()
+}
+
+// SetProfile changes the color profile output is downsampled to.
+func (e *ansiEngine) SetProfile(p Profile) { e.b.SetProfile(p) }
+
+// Invalidate repaints the nodes at keys, or everything, on the next frame.
+func (e *ansiEngine) Invalidate(keys ...Key) { invalidate(e.p, keys) }
+
+// NewTcellEngine returns an Engine drawing onto s, for programs that drive
+// the terminal themselves instead of going through Bubble Tea. The caller
+// owns s: Init it first, read input with s.PollEvent, pass s.Size() as the
+// bounds of each Reconcile (so a *tcell.EventResize takes effect on the next
+// frame), and Fini it on exit. Commit writes to the screen and returns "".
+// Works with tcell.NewSimulationScreen in tests.
+func NewTcellEngine(s tcell.Screen) (Engine, error) {
+	if s == nil { return nil, fmt.Errorf("ui: nil tcell screen") }
+	b := backend.NewTcell(s)
+	return &tcellEngine{p: pipeline.New(b), b: b}, nil
+}
+
+type tcellEngine struct {
+	p *pipeline.Pipeline
+	b *backend.Tcell
+}
+
+func (e *tcellEngine) Reconcile(prev, next Node, bounds Rect) RenderPlan {
+	e.p.Validate = DevMode()
+	return e.p.Plan(next, bounds)
+}
+
+// Problems lists the prop errors in the last tree (dev mode only).
+func (e *tcellEngine) Problems() []error { return problems(e.p.Problems()) }
+
+// Commit applies plan and shows it on the screen.
+func (e *tcellEngine) Commit(plan RenderPlan) string {
+	e.p.Apply(plan)
+	return e.b.Flush()
+}
+
+// Invalidate repaints the nodes at keys, or everything, on the next frame.
+func (e *tcellEngine) Invalidate(keys ...Key) { invalidate(e.p, keys) }
+
+func invalidate(p *pipeline.Pipeline, keys []Key) {
+	if len(keys) == 0 { p.Invalidate(); return }
+	p.InvalidateKeys(keys...)
+}
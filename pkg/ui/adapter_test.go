@@ -0,0 +1,51 @@
+package ui_test
+
+import (
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+)
+
+// newEngine returns a w×h ANSI engine with a fixed color profile.
+func newEngine(t *testing.T, w, h int) ui.Engine {
+	t.Helper()
+	e, err := ui.NewANSIEngine(w, h)
+	if err != nil { t.Fatal(err) }
+	e.(ui.ProfileSetter).SetProfile(ui.TrueColor)
+	return e
+}
+
+func label(s string) ui.Node { return ui.Box("root", ui.WithChildren(ui.Text("t", s, ui.Attr{}))) }
+
+func TestANSIEngineCommit(t *testing.T) {
+	bounds := ui.Rect{W: 5, H: 2}
+	e := newEngine(t, 5, 2)
+	if got, want := e.Commit(e.Reconcile(nil, label("hello"), bounds)), "hello\n     "; got != want { t.Errorf("first Commit = %q, want %q", got, want) }
+	if got, want := e.Commit(e.Reconcile(nil, label("help"), bounds)), "help \n     "; got != want { t.Errorf("Commit = %q, want the whole frame %q", got, want) }
+
+	d := newEngine(t, 5, 2)
+	dc := d.(ui.DiffCommitter)
+	if got, want := dc.CommitDiff(d.Reconcile(nil, label("hello"), bounds)), "\x1b[0m\x1b[H\x1b[2Jhello"; got != want { t.Errorf("first CommitDiff = %q, want %q", got, want) }
+	if got, want := dc.CommitDiff(d.Reconcile(nil, label("help"), bounds)), "\x1b[1;4Hp "; got != want { t.Errorf("CommitDiff = %q, want only the changed cells %q", got, want) }
+	if got := dc.CommitDiff(d.Reconcile(nil, label("help"), bounds)); got != "" { t.Errorf("CommitDiff of an unchanged tree = %q, want nothing", got) }
+}
+
+func TestANSIEngineResize(t *testing.T) {
+	e := newEngine(t, 5, 1)
+	if _, ok := e.(ui.Resizer); ok { t.Error("engine implements Resizer; its size comes from Reconcile's bounds") }
+	dc := e.(ui.DiffCommitter)
+	dc.CommitDiff(e.Reconcile(nil, label("hi"), ui.Rect{W: 5, H: 1}))
+
+	// Bounds of another size resize the frame and repaint it in full.
+	if got, want := dc.CommitDiff(e.Reconcile(nil, label("hi"), ui.Rect{W: 3, H: 1})), "\x1b[0m\x1b[H\x1b[2Jhi"; got != want { t.Errorf("CommitDiff at smaller bounds = %q, want a full repaint %q", got, want) }
+	if got, want := e.Commit(e.Reconcile(nil, label("hi"), ui.Rect{W: 3, H: 1})), "hi "; got != want { t.Errorf("Commit = %q, want %q", got, want) }
+	if got, want := dc.CommitDiff(e.Reconcile(nil, label("hi"), ui.Rect{W: 4, H: 2})), "\x1b[0m\x1b[H\x1b[2Jhi"; got != want { t.Errorf("CommitDiff at larger bounds = %q, want a full repaint %q", got, want) }
+	if got, want := e.Commit(e.Reconcile(nil, label("hi"), ui.Rect{W: 4, H: 2})), "hi  \n    "; got != want { t.Errorf("Commit = %q, want %q", got, want) }
+}
+
+func TestNewANSIEngineSize(t *testing.T) {
+	for _, s := range [][2]int{{-1, 2}, {2, -1}} {
+		if e, err := ui.NewANSIEngine(s[0], s[1]); err == nil || e != nil { t.Errorf("NewANSIEngine(%d, %d) = %v, %v; want an error", s[0], s[1], e, err) }
+	}
+	if _, err := ui.NewANSIEngine(0, 0); err != nil { t.Errorf("NewANSIEngine(0, 0) = %v", err) }
+}
@@ -1,16 +1,18 @@
 package ui
 
+import "github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+
 // NodeID is a stable identity used by reconciliation.
-type NodeID string
+type NodeID = renderer.NodeID
 
 // Node is the declarative element in the scene graph.
 // - Immutable across frames (encouraged) for simpler diffing.
 // - Props is intentionally generic; keep it small (numbers, strings, bools).
-type Node interface {
-	ID() NodeID
-	Children() []Node
-	Props() map[string]any
-}
+type Node = renderer.Node
+
+// Key addresses a node by the path of IDs from the root ("root/toolbar/save");
+// children without an ID are keyed by position ("#3").
+type Key = renderer.Key
 
 type nodeBase struct {
 	id   NodeID
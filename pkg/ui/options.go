@@ -1,5 +1,7 @@
 package ui
 
+import "github.com/GlitchedNexus/strawberry-tui/internal/renderer/layout"
+
 // NodeOption mutates construction-time properties for a Node.
 type NodeOption func(*nodeBase)
 
@@ -10,32 +12,226 @@ func WithChildren(children ...Node) NodeOption {
 
 // WithAttr attaches cell-level attributes a renderer can use by convention.
 func WithAttr(a Attr) NodeOption {
-	return func(nb *nodeBase) { nb.Props()["attr"] = a }
+	return func(nb *nodeBase) { PropAttr.Set(nb.Props(), a) }
 }
 
 // WithPadding sets box padding in cells (T, R, B, L).
-func WithPadding(pad struct{ T, R, B, L int }) NodeOption {
-	return func(nb *nodeBase) { nb.Props()["padding"] = pad }
+func WithPadding(pad Padding) NodeOption {
+	return func(nb *nodeBase) { PropPadding.Set(nb.Props(), pad) }
+}
+
+// WithMargin keeps space in cells clear around the node (T, R, B, L),
+// outside its border.
+func WithMargin(m Padding) NodeOption {
+	return func(nb *nodeBase) { PropMargin.Set(nb.Props(), m) }
 }
 
 // WithRadius sets visual corner radius (renderer decides how to realize it).
 func WithRadius(r int) NodeOption {
-	return func(nb *nodeBase) { nb.Props()["radius"] = r }
+	return func(nb *nodeBase) { PropRadius.Set(nb.Props(), r) }
+}
+
+// WithBorder draws a one-cell border in a (default colors inherit the box's).
+// Corners are square for radius 0 and rounded for radius >= 1.
+func WithBorder(a Attr) NodeOption {
+	return func(nb *nodeBase) { PropBorder.Set(nb.Props(), a) }
 }
 
 // WithSize hints preferred size (W,H). Renderer/layout may override.
 func WithSize(w, h int) NodeOption {
-	return func(nb *nodeBase) { nb.Props()["w"], nb.Props()["h"] = w, h }
+	return func(nb *nodeBase) { PropW.Set(nb.Props(), w); PropH.Set(nb.Props(), h) }
+}
+
+// WithMinWidth keeps the node at least n cells wide, however much it is
+// shrunk (e.g. a sidebar on a narrow terminal).
+func WithMinWidth(n int) NodeOption {
+	return func(nb *nodeBase) { PropMinW.Set(nb.Props(), n) }
+}
+
+// WithMaxWidth keeps the node at most n cells wide, however much it grows.
+func WithMaxWidth(n int) NodeOption {
+	return func(nb *nodeBase) { PropMaxW.Set(nb.Props(), n) }
+}
+
+// WithMinHeight keeps the node at least n rows tall.
+func WithMinHeight(n int) NodeOption {
+	return func(nb *nodeBase) { PropMinH.Set(nb.Props(), n) }
+}
+
+// WithMaxHeight keeps the node at most n rows tall.
+func WithMaxHeight(n int) NodeOption {
+	return func(nb *nodeBase) { PropMaxH.Set(nb.Props(), n) }
+}
+
+// WithWidthPercent sizes the node to pct% of its container's content width
+// (100 = full); it replaces a WithSize width.
+func WithWidthPercent(pct int) NodeOption {
+	return func(nb *nodeBase) { PropWPercent.Set(nb.Props(), pct) }
+}
+
+// WithHeightPercent sizes the node to pct% of its container's content height.
+func WithHeightPercent(pct int) NodeOption {
+	return func(nb *nodeBase) { PropHPercent.Set(nb.Props(), pct) }
+}
+
+// WithAspectRatio fixes width÷height in cells (terminal cells are about
+// twice as tall as wide, so a visually square box is ratio 2). With one side
+// known the other follows from it.
+func WithAspectRatio(ratio float64) NodeOption {
+	return func(nb *nodeBase) { PropAspect.Set(nb.Props(), ratio) }
 }
 
 // WithFlex sets flex grow/shrink/basis (used by your layout engine).
 func WithFlex(grow, shrink, basis int) NodeOption {
 	return func(nb *nodeBase) {
-		nb.Props()["grow"], nb.Props()["shrink"], nb.Props()["basis"] = grow, shrink, basis
+		p := nb.Props()
+		PropGrow.Set(p, grow)
+		PropShrink.Set(p, shrink)
+		PropBasis.Set(p, basis)
+	}
+}
+
+// WithDirection sets the main axis children flow along (Row by default).
+func WithDirection(d Direction) NodeOption {
+	return func(nb *nodeBase) { PropDirection.Set(nb.Props(), d) }
+}
+
+// WithGap sets the space in cells between children (both axes when wrapping).
+func WithGap(n int) NodeOption {
+	return func(nb *nodeBase) { PropGap.Set(nb.Props(), n) }
+}
+
+// WithJustify sets how leftover space on each line is spread around the
+// children (JustifyStart by default).
+func WithJustify(j Justify) NodeOption {
+	return func(nb *nodeBase) { PropJustify.Set(nb.Props(), j) }
+}
+
+// WithAlign sets how children sit across each line (AlignStretch by default).
+func WithAlign(a Align) NodeOption {
+	return func(nb *nodeBase) { PropAlign.Set(nb.Props(), a) }
+}
+
+// WithWrap lets children flow onto additional lines instead of shrinking.
+func WithWrap(on bool) NodeOption {
+	return func(nb *nodeBase) { PropWrap.Set(nb.Props(), on) }
+}
+
+// WithGridColumns makes the node a grid container with these column tracks.
+func WithGridColumns(tracks ...Track) NodeOption {
+	return func(nb *nodeBase) {
+		PropDisplay.Set(nb.Props(), layout.DisplayGrid)
+		PropGridCols.Set(nb.Props(), append([]Track(nil), tracks...))
 	}
 }
 
-// WithProp sets an arbitrary prop (escape hatch).
+// WithGridRows sets a grid's row tracks; rows beyond them are Auto.
+func WithGridRows(tracks ...Track) NodeOption {
+	return func(nb *nodeBase) {
+		PropDisplay.Set(nb.Props(), layout.DisplayGrid)
+		PropGridRows.Set(nb.Props(), append([]Track(nil), tracks...))
+	}
+}
+
+// WithGridAreas names grid cells, one string per row ("head head",
+// "side main"; "." leaves a cell unnamed). Children join an area with
+// WithArea. Columns not given by WithGridColumns are Auto.
+func WithGridAreas(rows ...string) NodeOption {
+	return func(nb *nodeBase) {
+		PropDisplay.Set(nb.Props(), layout.DisplayGrid)
+		PropGridAreas.Set(nb.Props(), append([]string(nil), rows...))
+	}
+}
+
+// WithGridGap sets the space between grid rows and between columns.
+func WithGridGap(row, col int) NodeOption {
+	return func(nb *nodeBase) { PropRowGap.Set(nb.Props(), row); PropColGap.Set(nb.Props(), col) }
+}
+
+// WithGridCell pins a grid item to a row and column (1-based; 0 leaves that
+// axis to auto placement).
+func WithGridCell(row, col int) NodeOption {
+	return func(nb *nodeBase) { PropRow.Set(nb.Props(), row); PropCol.Set(nb.Props(), col) }
+}
+
+// WithSpan makes a grid item cover rows×cols tracks.
+func WithSpan(rows, cols int) NodeOption {
+	return func(nb *nodeBase) { PropRowSpan.Set(nb.Props(), rows); PropColSpan.Set(nb.Props(), cols) }
+}
+
+// WithArea places a grid item in a named area (see WithGridAreas).
+func WithArea(name string) NodeOption {
+	return func(nb *nodeBase) { PropArea.Set(nb.Props(), name) }
+}
+
+// WithZ sets the stacking order among siblings (and among portals): higher
+// z paints on top, ties keep tree order.
+func WithZ(z int) NodeOption {
+	return func(nb *nodeBase) { PropZ.Set(nb.Props(), z) }
+}
+
+// WithAnchor sets what a Portal is positioned against.
+func WithAnchor(a Anchor) NodeOption {
+	return func(nb *nodeBase) { PropAnchor.Set(nb.Props(), a) }
+}
+
+// WithOffset moves a Portal x columns right and y rows down from its anchor.
+func WithOffset(x, y int) NodeOption {
+	return func(nb *nodeBase) { PropX.Set(nb.Props(), x); PropY.Set(nb.Props(), y) }
+}
+
+// WithPosition takes a node out of normal placement: Relative shifts it by
+// its offsets after flex layout, Absolute removes it from the flow and places
+// it against its parent's rect (see WithTop and friends).
+func WithPosition(p Position) NodeOption {
+	return func(nb *nodeBase) { PropPosition.Set(nb.Props(), p) }
+}
+
+// WithTop sets the offset from the parent's top edge (Absolute) or the
+// downward shift (Relative).
+func WithTop(n int) NodeOption {
+	return func(nb *nodeBase) { PropTop.Set(nb.Props(), n) }
+}
+
+// WithRight sets the offset from the parent's right edge (Absolute) or the
+// leftward shift (Relative).
+func WithRight(n int) NodeOption {
+	return func(nb *nodeBase) { PropRight.Set(nb.Props(), n) }
+}
+
+// WithBottom sets the offset from the parent's bottom edge (Absolute) or the
+// upward shift (Relative).
+func WithBottom(n int) NodeOption {
+	return func(nb *nodeBase) { PropBottom.Set(nb.Props(), n) }
+}
+
+// WithLeft sets the offset from the parent's left edge (Absolute) or the
+// rightward shift (Relative).
+func WithLeft(n int) NodeOption {
+	return func(nb *nodeBase) { PropLeft.Set(nb.Props(), n) }
+}
+
+// WithClass attaches utility classes ("bg-primary p-md focus:bold") for
+// theme.Apply to resolve with the theme in scope at the node.
+func WithClass(class string) NodeOption {
+	return func(nb *nodeBase) { PropClass.Set(nb.Props(), class) }
+}
+
+// Set stores v under a typed key (see NewPropKey for custom props).
+func Set[T any](k PropKey[T], v T) NodeOption {
+	return func(nb *nodeBase) { k.Set(nb.Props(), v) }
+}
+
+// WithProp sets an arbitrary prop (escape hatch). Nothing checks key or
+// type here; run Validate or dev mode to catch mistakes, and prefer Set with
+// a typed key for your own props.
 func WithProp(key string, v any) NodeOption {
 	return func(nb *nodeBase) { nb.Props()[key] = v }
 }
+
+// Apply runs opts against an existing node's props, e.g. styles resolved
+// after the tree was built. WithChildren has no effect here.
+func Apply(n Node, opts ...NodeOption) {
+	nb := nodeBase{id: n.ID(), kids: n.Children(), prop: n.Props()}
+	for _, opt := range opts { opt(&nb) }
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/layout"
+)
+
+// PropKey is a typed prop name: Get/Lookup read the prop only when it holds
+// a T, Set stores one. Prefer keys over raw Props() access so a typo is a
+// compile error rather than a prop that silently does nothing.
+type PropKey[T any] struct{ renderer.PropKey[T] }
+
+// NewPropKey registers a custom prop so Validate accepts it. Prefix custom
+// names (e.g. "data-role"); registering a name twice with different types
+// panics.
+func NewPropKey[T any](name string) PropKey[T] { return key(renderer.NewPropKey[T](name)) }
+
+func key[T any](k renderer.PropKey[T]) PropKey[T] { return PropKey[T]{k} }
+
+// Padding is box padding in cells.
+type Padding = renderer.Padding
+
+// Props read by the engine (set them with the matching With* option).
+var (
+	PropText      = key(renderer.PropText)
+	PropAttr      = key(renderer.PropAttr)
+	PropBorder    = key(renderer.PropBorder)
+	PropRadius    = key(renderer.PropRadius)
+	PropPadding   = key(renderer.PropPadding)
+	PropW         = key(renderer.PropW)
+	PropH         = key(renderer.PropH)
+	PropMinW      = key(renderer.PropMinW)
+	PropMaxW      = key(renderer.PropMaxW)
+	PropMinH      = key(renderer.PropMinH)
+	PropMaxH      = key(renderer.PropMaxH)
+	PropWPercent  = key(layout.PropWPercent)
+	PropHPercent  = key(layout.PropHPercent)
+	PropAspect    = key(layout.PropAspect)
+	PropGrow      = key(renderer.PropGrow)
+	PropShrink    = key(renderer.PropShrink)
+	PropBasis     = key(renderer.PropBasis)
+	PropGap       = key(renderer.PropGap)
+	PropWrap      = key(renderer.PropWrap)
+	PropDirection = key(layout.PropDirection)
+	PropJustify   = key(layout.PropJustify)
+	PropAlign     = key(layout.PropAlign)
+	PropMargin    = key(layout.PropMargin)
+	PropPosition  = key(layout.PropPosition)
+	PropTop       = key(layout.PropTop)
+	PropRight     = key(layout.PropRight)
+	PropBottom    = key(layout.PropBottom)
+	PropLeft      = key(layout.PropLeft)
+	PropDisplay   = key(layout.PropDisplay)
+	PropGridCols  = key(layout.PropGridCols)
+	PropGridRows  = key(layout.PropGridRows)
+	PropGridAreas = key(layout.PropGridAreas)
+	PropRowGap    = key(layout.PropRowGap)
+	PropColGap    = key(layout.PropColGap)
+	PropArea      = key(layout.PropArea)
+	PropRow       = key(layout.PropRow)
+	PropCol       = key(layout.PropCol)
+	PropRowSpan   = key(layout.PropRowSpan)
+	PropColSpan   = key(layout.PropColSpan)
+	PropZ         = key(renderer.PropZ)
+	PropPortal    = key(renderer.PropPortal)
+	PropAnchor    = key(layout.PropAnchor)
+	PropX         = key(renderer.PropX)
+	PropY         = key(renderer.PropY)
+)
+
+// PropClass holds a node's utility class string (see WithClass). The engine
+// doesn't read it; theme.Apply resolves it into the props above.
+var PropClass = NewPropKey[string]("class")
+
+// Validate reports every prop in the tree with an unknown name (outside the
+// "data-" and "aria-" namespaces) or a value of the wrong type, e.g.
+// WithProp("gorw", 1) or WithProp("grow", "1"). It returns nil when the tree
+// is clean.
+func Validate(root Node) error {
+	return errors.Join(problems(renderer.Validate(root))...)
+}
+
+var devMode atomic.Bool
+
+func init() { devMode.Store(os.Getenv("STRAWBERRY_DEV") != "") }
+
+// SetDevMode turns prop validation in engines on or off; it starts on when
+// the STRAWBERRY_DEV environment variable is set. Problems found are reported
+// through ProblemReporter rather than printed, so they never garble the
+// screen.
+func SetDevMode(on bool) { devMode.Store(on) }
+
+// DevMode reports whether engines validate the trees they render.
+func DevMode() bool { return devMode.Load() }
+
+// ProblemReporter is implemented by engines that validate trees in dev mode.
+// Problems returns what was wrong with the last reconciled tree.
+type ProblemReporter interface {
+	Problems() []error
+}
+
+func problems(errs []renderer.PropError) []error {
+	if len(errs) == 0 { return nil }
+	out := make([]error, len(errs))
+	for i, e := range errs { out[i] = fmt.Errorf("ui: %w", e) }
+	return out
+}
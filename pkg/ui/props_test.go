@@ -0,0 +1,43 @@
+package ui_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+)
+
+func TestValidate(t *testing.T) {
+	tree := ui.Box("root", ui.WithChildren(
+		ui.Box("a", ui.WithProp("gorw", 1)),
+		ui.Box("b", ui.WithProp("grow", "1")),
+		ui.Box("c", ui.WithFlex(1, 1, 0), ui.WithProp("data-id", 7)),
+	))
+	err := ui.Validate(tree)
+	if err == nil { t.Fatal("Validate = nil, want two problems") }
+	want := []string{
+		`ui: root/a: prop "gorw": unknown prop (did you mean "grow"?)`,
+		`ui: root/b: prop "grow": got string, want int`,
+	}
+	if got := err.Error(); got != strings.Join(want, "\n") { t.Errorf("Validate =\n%s\nwant\n%s", got, strings.Join(want, "\n")) }
+	if err := ui.Validate(ui.Box("root", ui.WithSize(3, 1))); err != nil { t.Errorf("Validate(clean) = %v", err) }
+}
+
+func TestProblemReporterInDevMode(t *testing.T) {
+	defer ui.SetDevMode(ui.DevMode())
+	e, err := ui.NewANSIEngine(4, 1)
+	if err != nil { t.Fatal(err) }
+	pr := e.(ui.ProblemReporter)
+	bad := ui.Box("root", ui.WithProp("gorw", 1))
+
+	ui.SetDevMode(false)
+	e.Commit(e.Reconcile(nil, bad, ui.Rect{W: 4, H: 1}))
+	if got := pr.Problems(); got != nil { t.Errorf("Problems outside dev mode = %v", got) }
+
+	ui.SetDevMode(true)
+	e.Commit(e.Reconcile(nil, bad, ui.Rect{W: 4, H: 1}))
+	if got := pr.Problems(); len(got) != 1 || !strings.Contains(got[0].Error(), `did you mean "grow"?`) { t.Errorf("Problems = %v, want the gorw typo", got) }
+
+	e.Commit(e.Reconcile(bad, ui.Box("root", ui.WithProp("grow", 1)), ui.Rect{W: 4, H: 1}))
+	if got := pr.Problems(); got != nil { t.Errorf("Problems after fixing the tree = %v", got) }
+}
@@ -1,16 +1,13 @@
 package ui
 
+import "github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+
 // CellOp is a primitive draw operation (renderer-internal but exposed for testing).
-type CellOp struct {
-	X, Y int
-	R    rune
-	A    Attr
-}
+type CellOp = renderer.CellOp
 
-// RenderPlan is what a reconciler/rasterizer produces for Commit.
-type RenderPlan struct {
-	Ops []CellOp
-}
+// RenderPlan is what a reconciler/rasterizer produces for Commit: the cell
+// ops plus the dirty rectangles they were clipped to.
+type RenderPlan = renderer.RenderPlan
 
 // Engine is the retained-mode renderer contract.
 // Your internal engine should satisfy this via an adapter.
@@ -18,3 +15,31 @@ type Engine interface {
 	Reconcile(prev, next Node, bounds Rect) RenderPlan
 	Commit(plan RenderPlan) string // returns frame string for Bubble Tea's View()
 }
+
+// Resizer is implemented by engines whose frame size is not set by the
+// bounds of Reconcile, such as uitest.Screen. The engines from NewANSIEngine
+// and NewTcellEngine take their size from those bounds instead.
+type Resizer interface {
+	Resize(w, h int)
+}
+
+// DiffCommitter is implemented by engines that can return just the escape
+// sequence turning the previous frame into the new one, which is far
+// cheaper than a full frame over slow links.
+type DiffCommitter interface {
+	CommitDiff(plan RenderPlan) string
+}
+
+// Invalidator is implemented by engines that can be told to repaint nodes
+// whose props changed in place (e.g. after theme.Apply on the tree they
+// last rendered), which reconciling a tree against itself cannot see.
+// No keys repaints everything.
+type Invalidator interface {
+	Invalidate(keys ...Key)
+}
+
+// ProfileSetter is implemented by engines whose color output can be
+// downsampled to a different terminal Profile.
+type ProfileSetter interface {
+	SetProfile(p Profile)
+}
@@ -1,14 +1,133 @@
 package ui
 
-// Color is a terminal color index (-1 = default).
-type Color int16
-
-// Attr are per-cell text attributes (kept minimal on purpose).
-type Attr struct {
-	FG, BG   Color
-	Bold     bool
-	Underline bool
+import (
+	"os"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer"
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/layout"
+	"github.com/charmbracelet/colorprofile"
+)
+
+// Color is a terminal color: default, one of the 16 ANSI colors, a 256-color
+// index or 24-bit RGB. The zero value is the terminal's default color.
+type Color = renderer.Color
+
+// DefaultColor leaves the terminal's own foreground/background in place.
+const DefaultColor = renderer.DefaultColor
+
+// ANSI returns one of the 16 basic colors (0–7 normal, 8–15 bright).
+func ANSI(i uint8) Color { return renderer.ANSI(i) }
+
+// Indexed returns an xterm 256-color palette entry.
+func Indexed(i uint8) Color { return renderer.Indexed(i) }
+
+// RGB returns a 24-bit color.
+func RGB(r, g, b uint8) Color { return renderer.RGB(r, g, b) }
+
+// Hex parses "#RRGGBB" or "#RGB".
+func Hex(s string) (Color, error) { return renderer.Hex(s) }
+
+// Profile is a terminal's color capability; engines downsample every color
+// to it (truecolor → 256 → 16) using perceptual nearest matching.
+type Profile = renderer.Profile
+
+const (
+	TrueColor = renderer.TrueColor
+	ANSI256   = renderer.ANSI256
+	ANSI16    = renderer.ANSI16
+	NoColor   = renderer.NoColor
+)
+
+// DetectProfile inspects stdout and the environment (COLORTERM, TERM,
+// NO_COLOR, CLICOLOR_FORCE, tmux) to find the color profile.
+func DetectProfile() Profile {
+	switch colorprofile.Detect(os.Stdout, os.Environ()) {
+	case colorprofile.TrueColor: return TrueColor
+	case colorprofile.ANSI256: return ANSI256
+	case colorprofile.ANSI: return ANSI16
+	}
+	return NoColor
 }
 
+// Attr are per-cell text attributes: colors, SGR flags (bold, italic, dim,
+// underline, strikethrough, reverse, blink) and underline style and color.
+type Attr = renderer.Attr
+
+// UnderlineStyle is the shape of an underline; it applies when Attr.Underline is set.
+type UnderlineStyle = renderer.UnderlineStyle
+
+const (
+	UnderlineSingle = renderer.UnderlineSingle
+	UnderlineDouble = renderer.UnderlineDouble
+	UnderlineCurly  = renderer.UnderlineCurly
+	UnderlineDotted = renderer.UnderlineDotted
+	UnderlineDashed = renderer.UnderlineDashed
+)
+
 // Rect is a cell-space rectangle.
-type Rect struct{ X, Y, W, H int }
+type Rect = renderer.Rect
+
+// Direction is the main axis of a flex container.
+type Direction = layout.Direction
+
+const (
+	Row    = layout.Row    // children flow left → right (default)
+	Column = layout.Column // children flow top → bottom
+)
+
+// Justify distributes a flex line's leftover main-axis space.
+type Justify = layout.Justify
+
+const (
+	JustifyStart   = layout.JustifyStart   // pack at the start (default)
+	JustifyEnd     = layout.JustifyEnd     // pack at the end
+	JustifyCenter  = layout.JustifyCenter  // center
+	JustifyBetween = layout.JustifyBetween // equal space between items
+	JustifyAround  = layout.JustifyAround  // equal space around items
+	JustifyEvenly  = layout.JustifyEvenly  // equal space between and at the edges
+)
+
+// Align places children on the cross axis of their flex line.
+type Align = layout.Align
+
+const (
+	AlignStretch = layout.AlignStretch // fill the line (default)
+	AlignStart   = layout.AlignStart
+	AlignEnd     = layout.AlignEnd
+	AlignCenter  = layout.AlignCenter
+)
+
+// Anchor is what a Portal's offset is measured from.
+type Anchor = layout.Anchor
+
+const (
+	AnchorParent = layout.AnchorParent // the parent's content origin (default)
+	AnchorRoot   = layout.AnchorRoot   // the screen's top-left
+	AnchorCenter = layout.AnchorCenter // centered on screen
+)
+
+// Position is how a node is placed relative to its siblings' flow.
+type Position = layout.Position
+
+const (
+	Static   = layout.Static   // in flow (default)
+	Relative = layout.Relative // in flow, then shifted by its offsets
+	Absolute = layout.Absolute // out of flow, placed against the parent rect
+)
+
+// Track is one row or column of a grid container.
+type Track = layout.Track
+
+// Cells is a fixed track n cells wide (or tall).
+func Cells(n int) Track { return Track{Kind: layout.TrackFixed, N: n} }
+
+// Fr is a flexible track taking n shares of the space left after fixed and
+// auto tracks.
+func Fr(n int) Track { return Track{Kind: layout.TrackFr, N: n} }
+
+// Auto is a track sized to its largest single-span item.
+var Auto = Track{Kind: layout.TrackAuto}
+
+// AutoFill, as a grid's only column track, makes as many equal columns of at
+// least min cells as fit, so tiles reflow when the terminal is resized.
+func AutoFill(min int) Track { return Track{Kind: layout.TrackAutoFill, N: min} }
@@ -0,0 +1,56 @@
+package uitest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/layout"
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+)
+
+// AssertGlyph fails t unless cell (x, y) shows the grapheme cluster want.
+func AssertGlyph(t testing.TB, s *Screen, x, y int, want string) {
+	t.Helper()
+	if got := s.Cell(x, y).Text; got != want { t.Errorf("uitest: glyph at (%d,%d) = %q, want %q", x, y, got, want) }
+}
+
+// AssertAttr fails t unless cell (x, y) is drawn with want.
+func AssertAttr(t testing.TB, s *Screen, x, y int, want ui.Attr) {
+	t.Helper()
+	if got := s.Cell(x, y).Attr; got != want { t.Errorf("uitest: attr at (%d,%d) = %+v, want %+v", x, y, got, want) }
+}
+
+// AssertText fails t unless row y shows want starting at column x (wide
+// clusters count as two columns).
+func AssertText(t testing.TB, s *Screen, x, y int, want string) {
+	t.Helper()
+	var sb strings.Builder
+	for i := 0; i < layout.StringWidth(want); i++ { sb.WriteString(s.Cell(x+i, y).Text) }
+	if got := sb.String(); got != want { t.Errorf("uitest: text at (%d,%d) = %q, want %q (row: %q)", x, y, got, want, s.Line(y)) }
+}
+
+// AssertOps fails t unless the last frame wrote exactly want cells.
+func AssertOps(t testing.TB, s *Screen, want int) {
+	t.Helper()
+	if got := len(s.Last().Ops); got != want { t.Errorf("uitest: last frame wrote %d cells, want %d", got, want) }
+}
+
+// AssertDirty fails t unless every cell the last frame wrote lies inside
+// one of allowed.
+func AssertDirty(t testing.TB, s *Screen, allowed ...ui.Rect) {
+	t.Helper()
+	for _, op := range s.Last().Ops {
+		inside := false
+		for _, r := range allowed { inside = inside || r.Contains(op.X, op.Y) }
+		if !inside {
+			t.Errorf("uitest: last frame wrote (%d,%d) outside %v", op.X, op.Y, allowed)
+			return
+		}
+	}
+}
+
+// AssertValid fails t if n has unknown or mistyped props (see ui.Validate).
+func AssertValid(t testing.TB, n ui.Node) {
+	t.Helper()
+	if err := ui.Validate(n); err != nil { t.Errorf("uitest: invalid props:\n%v", err) }
+}
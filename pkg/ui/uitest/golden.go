@@ -0,0 +1,55 @@
+package uitest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// update rewrites golden files instead of comparing: go test ./... -update
+//
+// Packages using uitest must not register their own -update flag.
+var update = flag.Bool("update", false, "uitest: rewrite golden files")
+
+// GoldenDir is where golden files live, relative to the test's package.
+var GoldenDir = "testdata"
+
+// Golden compares the screen's plain text with testdata/<name>.golden,
+// failing t with the first differing row. With -update it writes the file
+// instead.
+func Golden(t testing.TB, s *Screen, name string) {
+	t.Helper()
+	golden(t, name+".golden", s.Text()+"\n")
+}
+
+// GoldenANSI is like Golden but compares the styled frame, so color and
+// attribute changes fail too. Files are named <name>.ansi.golden.
+func GoldenANSI(t testing.TB, s *Screen, name string) {
+	t.Helper()
+	golden(t, name+".ansi.golden", s.Frame()+"\n")
+}
+
+func golden(t testing.TB, file, got string) {
+	t.Helper()
+	path := filepath.Join(GoldenDir, file)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { t.Fatalf("uitest: %v", err) }
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil { t.Fatalf("uitest: %v", err) }
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil { t.Fatalf("uitest: %v (run with -update to create it)", err) }
+	if got == string(want) { return }
+	g, w := strings.Split(got, "\n"), strings.Split(string(want), "\n")
+	for i := 0; i < max(len(g), len(w)); i++ {
+		var gl, wl string
+		if i < len(g) { gl = g[i] }
+		if i < len(w) { wl = w[i] }
+		if gl != wl {
+			t.Errorf("uitest: %s differs at row %d:\n got: %q\nwant: %q\n(run with -update to accept)", path, i, gl, wl)
+			return
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Package uitest renders ui trees headlessly so screens can be tested in CI
+// without a TTY.
+//
+//	s := uitest.Render(view(), 40, 10)
+//	uitest.AssertText(t, s, 2, 1, "Save")
+//	uitest.Golden(t, s, "toolbar")
+//
+// A Screen is a full ui.Engine over an in-memory buffer: feed it successive
+// trees with Render to check what each frame repaints (RenderPlan ops and
+// dirty rects) as well as what ends up on screen.
+package uitest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/backend"
+	"github.com/GlitchedNexus/strawberry-tui/internal/renderer/pipeline"
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+)
+
+// Screen is an in-memory engine. Colors are kept at TrueColor so frames do
+// not depend on the terminal running the tests. It is not safe for
+// concurrent use.
+type Screen struct {
+	p     *pipeline.Pipeline
+	b     *backend.ANSI
+	plans []ui.RenderPlan
+}
+
+var (
+	_ ui.Engine          = (*Screen)(nil)
+	_ ui.Resizer         = (*Screen)(nil)
+	_ ui.ProfileSetter   = (*Screen)(nil)
+	_ ui.ProblemReporter = (*Screen)(nil)
+	_ ui.Invalidator     = (*Screen)(nil)
+)
+
+// New returns a blank w×h screen.
+func New(w, h int) *Screen {
+	b := backend.NewANSI(max(w, 0), max(h, 0))
+	return &Screen{p: pipeline.New(b), b: b}
+}
+
+// Render returns a w×h screen showing n.
+func Render(n ui.Node, w, h int) *Screen {
+	s := New(w, h)
+	s.Render(n)
+	return s
+}
+
+// Render plans and commits n at the screen's current size and returns the
+// plan, which is also kept in Plans.
+func (s *Screen) Render(n ui.Node) ui.RenderPlan {
+	w, h := s.Size()
+	plan := s.Reconcile(nil, n, ui.Rect{W: w, H: h})
+	s.Commit(plan)
+	return plan
+}
+
+// Reconcile implements ui.Engine; like the ANSI engine it keeps the previous
+// tree itself, so prev is ignored.
+func (s *Screen) Reconcile(prev, next ui.Node, bounds ui.Rect) ui.RenderPlan {
+	s.p.Validate = ui.DevMode()
+	return s.p.Plan(next, bounds)
+}
+
+// Problems lists the prop errors in the last tree (dev mode only; see
+// AssertValid to check regardless of mode).
+func (s *Screen) Problems() []error {
+	var out []error
+	for _, e := range s.p.Problems() { out = append(out, fmt.Errorf("uitest: %w", e)) }
+	return out
+}
+
+// Commit implements ui.Engine, recording plan and returning the frame.
+func (s *Screen) Commit(plan ui.RenderPlan) string {
+	s.plans = append(s.plans, plan)
+	s.p.Apply(plan)
+	s.b.Flush()
+	return s.b.Frame()
+}
+
+// Resize changes the screen size; the next frame is repainted in full.
+func (s *Screen) Resize(w, h int) {
+	s.b.Resize(max(w, 0), max(h, 0))
+	s.p.Invalidate()
+}
+
+// Invalidate repaints the nodes at keys, or everything, on the next frame.
+func (s *Screen) Invalidate(keys ...ui.Key) {
+	if len(keys) == 0 { s.p.Invalidate(); return }
+	s.p.InvalidateKeys(keys...)
+}
+
+// SetProfile downsamples Frame output to p, for testing low-color fallbacks.
+func (s *Screen) SetProfile(p ui.Profile) { s.b.SetProfile(p) }
+
+// Size returns the screen size in cells.
+func (s *Screen) Size() (w, h int) { return s.b.Size() }
+
+// Plans returns every committed plan, oldest first.
+func (s *Screen) Plans() []ui.RenderPlan { return s.plans }
+
+// Last returns the most recently committed plan.
+func (s *Screen) Last() ui.RenderPlan {
+	if len(s.plans) == 0 { return ui.RenderPlan{} }
+	return s.plans[len(s.plans)-1]
+}
+
+// Cell is what the screen shows at one position.
+type Cell struct {
+	Text  string // grapheme cluster; "" on the right half of a wide one
+	Width int    // 1, 2, or 0 for the right half of a wide cluster
+	Attr  ui.Attr
+}
+
+// Cell returns the cell at (x, y); out-of-range cells are blank.
+func (s *Screen) Cell(x, y int) Cell {
+	c := s.b.Cell(x, y)
+	return Cell{Text: c.Text(), Width: int(c.W), Attr: c.A}
+}
+
+// Line returns row y as plain text with trailing blanks trimmed.
+func (s *Screen) Line(y int) string {
+	w, _ := s.Size()
+	var sb strings.Builder
+	for x := 0; x < w; x++ { sb.WriteString(s.Cell(x, y).Text) }
+	return strings.TrimRight(sb.String(), " ")
+}
+
+// Text returns the screen as plain text, one line per row, trailing blanks
+// trimmed.
+func (s *Screen) Text() string {
+	_, h := s.Size()
+	lines := make([]string, h)
+	for y := range lines { lines[y] = s.Line(y) }
+	return strings.Join(lines, "\n")
+}
+
+// Frame returns the screen as styled ANSI rows, as ui.Engine.Commit would.
+func (s *Screen) Frame() string { return s.b.Frame() }
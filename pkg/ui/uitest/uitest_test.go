@@ -0,0 +1,127 @@
+package uitest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/GlitchedNexus/strawberry-tui/pkg/ui"
+)
+
+// recorder is a testing.TB that keeps failure messages instead of failing.
+type recorder struct {
+	testing.TB
+	msgs  []string
+	fatal bool
+}
+
+func (r *recorder) Helper()                       {}
+func (r *recorder) Errorf(format string, a ...any) { r.msgs = append(r.msgs, fmt.Sprintf(format, a...)) }
+func (r *recorder) Fatalf(format string, a ...any) { r.Errorf(format, a...); r.fatal = true; runtime.Goexit() }
+
+// record runs f on its own goroutine so Fatalf can stop it like t.Fatalf.
+func record(t *testing.T, f func(tb testing.TB)) *recorder {
+	r := &recorder{TB: t}
+	done := make(chan struct{})
+	go func() { defer close(done); f(r) }()
+	<-done
+	return r
+}
+
+func wantMsg(t *testing.T, r *recorder, want string) {
+	t.Helper()
+	if len(r.msgs) != 1 || !strings.Contains(r.msgs[0], want) { t.Errorf("messages = %q, want one containing %q", r.msgs, want) }
+}
+
+func toolbar(label string) ui.Node {
+	return ui.Box("root", ui.WithDirection(ui.Row), ui.WithGap(1), ui.WithChildren(
+		ui.Text("save", "Save", ui.Attr{Bold: true}),
+		ui.Text("label", label, ui.Attr{}),
+	))
+}
+
+func TestRender(t *testing.T) {
+	s := Render(toolbar("ready"), 12, 2)
+	if w, h := s.Size(); w != 12 || h != 2 { t.Fatalf("size = %dx%d, want 12x2", w, h) }
+	if got := s.Text(); got != "Save ready\n" { t.Errorf("Text = %q", got) }
+	AssertText(t, s, 0, 0, "Save")
+	AssertText(t, s, 5, 0, "ready")
+	AssertGlyph(t, s, 4, 0, " ")
+	AssertAttr(t, s, 0, 0, ui.Attr{Bold: true})
+	AssertOps(t, s, 24)
+	if len(s.Plans()) != 1 { t.Errorf("Plans = %d, want 1", len(s.Plans())) }
+}
+
+func TestRenderRepaintsOnlyChanges(t *testing.T) {
+	s := Render(toolbar("ready"), 12, 2)
+	s.Render(toolbar("ready"))
+	AssertOps(t, s, 0)
+	s.Render(toolbar("busy!"))
+	// Items stretch to the row's height, so the label's box is 5×2.
+	AssertDirty(t, s, ui.Rect{X: 5, Y: 0, W: 5, H: 2})
+	AssertText(t, s, 5, 0, "busy!")
+}
+
+func TestWideText(t *testing.T) {
+	s := Render(ui.Text("t", "日本", ui.Attr{}), 6, 1)
+	AssertText(t, s, 0, 0, "日本")
+	if c := s.Cell(1, 0); c.Width != 0 || c.Text != "" { t.Errorf("right half = %+v, want empty continuation", c) }
+}
+
+func TestAssertFailures(t *testing.T) {
+	s := Render(toolbar("ready"), 12, 2)
+	tests := []struct {
+		name string
+		f    func(tb testing.TB)
+		want string
+	}{
+		{"text", func(tb testing.TB) { AssertText(tb, s, 0, 0, "Load") }, `text at (0,0) = "Save", want "Load" (row: "Save ready")`},
+		{"glyph", func(tb testing.TB) { AssertGlyph(tb, s, 1, 0, "x") }, `glyph at (1,0) = "a", want "x"`},
+		{"attr", func(tb testing.TB) { AssertAttr(tb, s, 5, 0, ui.Attr{Bold: true}) }, `attr at (5,0)`},
+		{"ops", func(tb testing.TB) { AssertOps(tb, s, 3) }, "last frame wrote 24 cells, want 3"},
+		{"dirty", func(tb testing.TB) { AssertDirty(tb, s, ui.Rect{W: 4, H: 1}) }, "last frame wrote (4,0) outside"},
+		{"valid", func(tb testing.TB) { AssertValid(tb, ui.Box("b", ui.WithProp("colour", 1))) }, "invalid props"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { wantMsg(t, record(t, tt.f), tt.want) })
+	}
+}
+
+func TestGolden(t *testing.T) {
+	if *update { t.Skip("checks comparison failures; -update would write them") }
+	s := Render(toolbar("ready"), 12, 2)
+	Golden(t, s, "toolbar")
+
+	r := record(t, func(tb testing.TB) { Golden(tb, Render(toolbar("busy"), 12, 2), "toolbar") })
+	wantMsg(t, r, `testdata/toolbar.golden differs at row 0:
+ got: "Save busy"
+want: "Save ready"`)
+
+	r = record(t, func(tb testing.TB) { Golden(tb, s, "missing") })
+	if !r.fatal { t.Error("missing golden file did not fail fatally") }
+	wantMsg(t, r, "run with -update to create it")
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "testdata")
+	defer func(d string, u bool) { GoldenDir, *update = d, u }(GoldenDir, *update)
+	GoldenDir, *update = dir, true
+
+	s := Render(toolbar("ready"), 12, 2)
+	Golden(t, s, "toolbar")
+	GoldenANSI(t, s, "toolbar")
+	got, err := os.ReadFile(filepath.Join(dir, "toolbar.golden"))
+	if err != nil || string(got) != "Save ready\n\n" { t.Fatalf("written golden = %q, %v", got, err) }
+	want, _ := os.ReadFile(filepath.Join("testdata", "toolbar.golden"))
+	if string(got) != string(want) { t.Errorf("-update wrote %q, checked-in file has %q", got, want) }
+	if ansi, err := os.ReadFile(filepath.Join(dir, "toolbar.ansi.golden")); err != nil || !strings.Contains(string(ansi), "\x1b[1m") {
+		t.Errorf("ansi golden = %q, %v; want bold SGR", ansi, err)
+	}
+
+	*update = false
+	Golden(t, s, "toolbar")
+	GoldenANSI(t, s, "toolbar")
+}
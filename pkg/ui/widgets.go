@@ -4,7 +4,7 @@ package ui
 type textNode struct{ nodeBase }
 
 func Text(id, content string, a Attr, opts ...NodeOption) Node {
-	nb := nodeBase{id: NodeID(id), prop: map[string]any{"text": content, "attr": a}}
+	nb := nodeBase{id: NodeID(id), prop: map[string]any{PropText.Name(): content, PropAttr.Name(): a}}
 	for _, opt := range opts { opt(&nb) }
 	return &textNode{nb}
 }
@@ -18,3 +18,15 @@ func Box(id string, opts ...NodeOption) Node {
 	for _, opt := range opts { opt(&nb) }
 	return &boxNode{nb}
 }
+
+// Portal is a container rendered in a root-level layer above the rest of the
+// tree: it takes no space in its parent, is not clipped by it, and is placed
+// by WithAnchor/WithOffset and sized by WithSize (or its content). Use it for
+// dropdowns, tooltips and modals; WithZ orders overlapping portals.
+type portalNode struct{ nodeBase }
+
+func Portal(id string, opts ...NodeOption) Node {
+	nb := nodeBase{id: NodeID(id), prop: map[string]any{PropPortal.Name(): true}}
+	for _, opt := range opts { opt(&nb) }
+	return &portalNode{nb}
+}